@@ -0,0 +1,432 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/nao1215/hottest/testjson"
+)
+
+const (
+	// benchSignificance is the significance level of the Mann-Whitney U test.
+	// A delta whose p-value is this value or more is regarded as noise, as benchstat does.
+	benchSignificance = 0.05
+	// minBenchCount is the minimum -count with which a delta can be significant when the baseline and
+	// the current run have the same number of samples. The smallest p-value of n=3+3 is 2/C(6,3) = 0.1.
+	minBenchCount = 4
+	// maxExactSamples is the maximum product of the sample sizes for which the exact
+	// distribution of the U statistic is computed. The normal approximation is used for larger samples.
+	maxExactSamples = 2500
+)
+
+// errBenchRegression is an error that occurs when a benchmark is slower than the baseline by more than the threshold.
+var errBenchRegression = errors.New("benchmark regression")
+
+// benchBaseline is the benchmark results that are saved by -hottest.bench-baseline.
+type benchBaseline struct {
+	// Saved is the time when the baseline was saved.
+	Saved time.Time `json:"saved"`
+	// Commit is the git commit hash of the run. It is empty if the git command is not available.
+	Commit string `json:"commit"`
+	// Branch is the git branch of the run. It is empty if the git command is not available.
+	Branch string `json:"branch"`
+	// Benchmarks is the benchmark results. The samples of -count are saved as separate results.
+	Benchmarks []*testjson.Benchmark `json:"benchmarks"`
+}
+
+// saveBenchBaseline saves the benchmark results to the file.
+func saveBenchBaseline(path string, benchmarks []*testjson.Benchmark) error {
+	b, err := json.MarshalIndent(benchBaseline{
+		Saved:      time.Now().UTC(),
+		Commit:     gitOutput("rev-parse", "HEAD"),
+		Branch:     gitOutput("rev-parse", "--abbrev-ref", "HEAD"),
+		Benchmarks: benchmarks,
+	}, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(filepath.Clean(path), b, 0o600); err != nil {
+		return fmt.Errorf("failed to save benchmark baseline: %w", err)
+	}
+	return nil
+}
+
+// loadBenchBaseline loads the benchmark results saved by saveBenchBaseline.
+// If the file does not exist, the error wraps os.ErrNotExist.
+func loadBenchBaseline(path string) (*benchBaseline, error) {
+	b, err := os.ReadFile(filepath.Clean(path))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read benchmark baseline: %w", err)
+	}
+	baseline := &benchBaseline{}
+	if err := json.Unmarshal(b, baseline); err != nil {
+		return nil, fmt.Errorf("failed to parse benchmark baseline %s: %w", path, err)
+	}
+	return baseline, nil
+}
+
+// benchComparison is the comparison of a metric of a benchmark between the baseline and the current run.
+type benchComparison struct {
+	// name is the benchmark name with the source label and the GOMAXPROCS suffix.
+	name string
+	pkg  string
+	unit string
+	// before and after are the samples of the baseline and the current run.
+	before []float64
+	after  []float64
+	// delta is the rate of change of the median, e.g. 0.1 if the current run is 10% larger.
+	delta float64
+	// p is the p-value of the Mann-Whitney U test.
+	p float64
+}
+
+// significant returns true if the delta is not regarded as noise.
+func (c benchComparison) significant() bool {
+	return c.p < benchSignificance
+}
+
+// canBeSignificant returns false if the samples are too few for any delta to be significant,
+// i.e. the smallest p-value of the exact test, 2/C(n1+n2, n1), is not less than benchSignificance.
+func (c benchComparison) canBeSignificant() bool {
+	n1, n2 := len(c.before), len(c.after)
+	if n1 == 0 || n2 == 0 {
+		return false
+	}
+	ways := 1.0
+	for i := 1; i <= n1; i++ {
+		ways = ways * float64(n2+i) / float64(i)
+	}
+	return 2/ways < benchSignificance
+}
+
+// regressed returns true if the metric significantly increased by more than the threshold.
+// All compared metrics (ns/op, B/op and allocs/op) are better when they are smaller.
+func (c benchComparison) regressed(threshold float64) bool {
+	return c.significant() && c.delta > threshold
+}
+
+// compareBenchmarks compares the samples of each benchmark in the current run with the baseline.
+// The benchmarks that are not in the baseline are omitted. The comparisons are in the order of the current run.
+func compareBenchmarks(before, after []*testjson.Benchmark) []benchComparison {
+	beforeSamples := groupBenchmarks(before)
+	keys := []string{}
+	afterSamples := map[string][]*testjson.Benchmark{}
+	for _, b := range after {
+		key := benchmarkKey(b)
+		if _, ok := afterSamples[key]; !ok {
+			keys = append(keys, key)
+		}
+		afterSamples[key] = append(afterSamples[key], b)
+	}
+
+	comparisons := []benchComparison{}
+	for _, key := range keys {
+		olds, news := beforeSamples[key], afterSamples[key]
+		if len(olds) == 0 {
+			continue
+		}
+		units := []string{testjson.UnitNsPerOp}
+		if olds[0].HasMemory && news[0].HasMemory {
+			units = append(units, testjson.UnitBytesPerOp, testjson.UnitAllocsPerOp)
+		}
+		for _, unit := range units {
+			c := benchComparison{
+				name:   news[0].Label() + news[0].FullName(),
+				pkg:    news[0].Package,
+				unit:   unit,
+				before: benchmarkValues(olds, unit),
+				after:  benchmarkValues(news, unit),
+			}
+			if m := median(c.before); m != 0 {
+				c.delta = (median(c.after) - m) / m
+			}
+			c.p = mannWhitneyUTest(c.before, c.after)
+			comparisons = append(comparisons, c)
+		}
+	}
+	return comparisons
+}
+
+// groupBenchmarks groups the samples by benchmarkKey.
+func groupBenchmarks(benchmarks []*testjson.Benchmark) map[string][]*testjson.Benchmark {
+	groups := map[string][]*testjson.Benchmark{}
+	for _, b := range benchmarks {
+		groups[benchmarkKey(b)] = append(groups[benchmarkKey(b)], b)
+	}
+	return groups
+}
+
+// benchmarkKey returns the key that identifies the benchmark across runs.
+func benchmarkKey(b *testjson.Benchmark) string {
+	return b.Label() + b.Package + " " + b.FullName()
+}
+
+// benchmarkValues returns the values of the unit of the samples.
+func benchmarkValues(samples []*testjson.Benchmark, unit string) []float64 {
+	values := make([]float64, 0, len(samples))
+	for _, b := range samples {
+		switch unit {
+		case testjson.UnitNsPerOp:
+			values = append(values, b.NsPerOp)
+		case testjson.UnitBytesPerOp:
+			values = append(values, float64(b.BytesPerOp))
+		case testjson.UnitAllocsPerOp:
+			values = append(values, float64(b.AllocsPerOp))
+		}
+	}
+	return values
+}
+
+// median returns the median of the values. It returns 0 if values is empty.
+func median(values []float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+	sorted := append([]float64{}, values...)
+	sort.Float64s(sorted)
+	if n := len(sorted); n%2 == 0 {
+		return (sorted[n/2-1] + sorted[n/2]) / 2
+	}
+	return sorted[len(sorted)/2]
+}
+
+// mannWhitneyUTest returns the two-sided p-value of the Mann-Whitney U test, which tests
+// whether the samples x and y come from the same distribution without assuming the normal distribution.
+// The exact distribution of U is used for small samples without ties, and the normal approximation
+// with the tie correction is used otherwise.
+func mannWhitneyUTest(x, y []float64) float64 {
+	n1, n2 := len(x), len(y)
+	if n1 == 0 || n2 == 0 {
+		return 1
+	}
+
+	type sample struct {
+		value float64
+		inX   bool
+	}
+	samples := make([]sample, 0, n1+n2)
+	for _, v := range x {
+		samples = append(samples, sample{value: v, inX: true})
+	}
+	for _, v := range y {
+		samples = append(samples, sample{value: v})
+	}
+	sort.Slice(samples, func(i, j int) bool { return samples[i].value < samples[j].value })
+
+	// The tied values get the average of their ranks.
+	n := len(samples)
+	rankSumX, tieTerm := 0.0, 0.0
+	for i := 0; i < n; {
+		j := i
+		for j < n && samples[j].value == samples[i].value {
+			j++
+		}
+		rank := float64(i+j+1) / 2
+		for k := i; k < j; k++ {
+			if samples[k].inX {
+				rankSumX += rank
+			}
+		}
+		if t := float64(j - i); t > 1 {
+			tieTerm += t*t*t - t
+		}
+		i = j
+	}
+
+	u := rankSumX - float64(n1*(n1+1))/2
+	u = math.Min(u, float64(n1*n2)-u)
+
+	var p float64
+	if tieTerm == 0 && n1*n2 <= maxExactSamples {
+		p = 2 * exactUCDF(n1, n2, int(u))
+	} else {
+		mean := float64(n1*n2) / 2
+		sigma := math.Sqrt(float64(n1*n2) / 12 * (float64(n+1) - tieTerm/float64(n*(n-1))))
+		if sigma == 0 {
+			return 1
+		}
+		z := math.Max(mean-u-0.5, 0) / sigma
+		p = math.Erfc(z / math.Sqrt2)
+	}
+	return math.Min(p, 1)
+}
+
+// exactUCDF returns P(U <= u) of the Mann-Whitney U statistic for the sample sizes n1 and n2 without ties.
+func exactUCDF(n1, n2, u int) float64 {
+	// counts[k][v] is the number of ways to place k samples of x so that U is v. When the i-th smallest
+	// value is the (k+1)-th sample of x, it is larger than i-k samples of y.
+	counts := make([][]float64, n1+1)
+	for k := range counts {
+		counts[k] = make([]float64, n1*n2+1)
+	}
+	counts[0][0] = 1
+	for i := 0; i < n1+n2; i++ {
+		k := i
+		if k > n1-1 {
+			k = n1 - 1
+		}
+		for ; k >= 0; k-- {
+			above := i - k
+			if above > n2 {
+				continue
+			}
+			for v := n1*n2 - above; v >= 0; v-- {
+				counts[k+1][v+above] += counts[k][v]
+			}
+		}
+	}
+
+	total, below := 0.0, 0.0
+	for v, c := range counts[n1] {
+		total += c
+		if v <= u {
+			below += c
+		}
+	}
+	return below / total
+}
+
+// compareBenchBaselineIfNeeded saves the benchmark results to the baseline file of -hottest.bench-baseline
+// if it does not exist, or compares the results with it and prints the comparison.
+// It returns errBenchRegression if a benchmark regressed by more than -hottest.bench-threshold.
+func (h *hottest) compareBenchBaselineIfNeeded(passed bool) error {
+	if h.opts.benchBaseline == "" {
+		return nil
+	}
+	benchmarks := h.Results.Benchmarks()
+	if len(benchmarks) == 0 {
+		fmt.Fprintf(os.Stdout, "no benchmark results to compare with %s: run with -bench\n", h.opts.benchBaseline)
+		return nil
+	}
+
+	baseline, err := loadBenchBaseline(h.opts.benchBaseline)
+	if errors.Is(err, os.ErrNotExist) {
+		if !passed {
+			fmt.Fprintf(os.Stdout, "the benchmark baseline is not saved because the tests failed\n")
+			return nil
+		}
+		if err := saveBenchBaseline(h.opts.benchBaseline, benchmarks); err != nil {
+			return err
+		}
+		fmt.Fprintf(os.Stdout, "saved %d benchmark results to %s as the baseline\n", len(benchmarks), h.opts.benchBaseline)
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	comparisons := compareBenchmarks(baseline.Benchmarks, benchmarks)
+	printBenchComparison(os.Stdout, comparisons, baseline, h.opts.benchThreshold)
+
+	if h.opts.benchThreshold <= 0 {
+		return nil
+	}
+	regressions := 0
+	for _, c := range comparisons {
+		if c.regressed(h.opts.benchThreshold) {
+			regressions++
+		}
+	}
+	if regressions > 0 {
+		return fmt.Errorf("%w: %d benchmark metrics are worse than the baseline by more than %.0f%%",
+			errBenchRegression, regressions, h.opts.benchThreshold*100)
+	}
+	return nil
+}
+
+// printBenchComparison prints the comparison with the baseline, e.g.
+// "BenchmarkX-8  ns/op  1234  1400  +13.45% (p=0.008 n=5+5)  example.com/a".
+// The delta that is not significant is printed as "~". The significant regressions are red
+// and the significant improvements are green. If the samples of a comparison are too few to be
+// significant, a warning that suggests minBenchCount is printed after the table.
+func printBenchComparison(w io.Writer, comparisons []benchComparison, baseline *benchBaseline, threshold float64) {
+	fmt.Fprintf(w, "[Benchmark Comparison] (baseline: %s)\n", describeBaseline(baseline))
+	if len(comparisons) == 0 {
+		fmt.Fprintf(w, " no benchmarks in common with the baseline\n")
+		return
+	}
+
+	header := []string{"BENCHMARK", "UNIT", "BASELINE", "CURRENT", "DELTA", "PACKAGE"}
+	rows := make([][]string, 0, len(comparisons))
+	for _, c := range comparisons {
+		delta := "~"
+		if c.significant() {
+			delta = fmt.Sprintf("%+.2f%%", c.delta*100)
+		}
+		rows = append(rows, []string{
+			c.name,
+			c.unit,
+			formatBenchCenter(median(c.before)),
+			formatBenchCenter(median(c.after)),
+			fmt.Sprintf("%s (p=%.3f n=%d+%d)", delta, c.p, len(c.before), len(c.after)),
+			c.pkg,
+		})
+	}
+
+	widths := make([]int, len(header))
+	for _, row := range append([][]string{header}, rows...) {
+		for i, cell := range row {
+			if len(cell) > widths[i] {
+				widths[i] = len(cell)
+			}
+		}
+	}
+	printRow := func(row []string, paint func(format string, a ...interface{}) string) {
+		fmt.Fprintf(w, " %-*s  %-*s  %*s  %*s  %s  %s\n",
+			widths[0], row[0], widths[1], row[1], widths[2], row[2], widths[3], row[3],
+			paint("%-*s", widths[4], row[4]), row[5])
+	}
+	printRow(header, fmt.Sprintf)
+	for i, c := range comparisons {
+		switch {
+		case c.regressed(threshold):
+			printRow(rows[i], color.RedString)
+		case c.significant() && c.delta < 0:
+			printRow(rows[i], color.GreenString)
+		default:
+			printRow(rows[i], fmt.Sprintf)
+		}
+	}
+
+	tooFew := 0
+	for _, c := range comparisons {
+		if !c.canBeSignificant() {
+			tooFew++
+		}
+	}
+	if tooFew > 0 {
+		fmt.Fprint(w, color.YellowString(" warning: %d of %d comparisons have too few samples to be significant: "+
+			"run the benchmarks with -count=%d or more for both the baseline and the current run\n",
+			tooFew, len(comparisons), minBenchCount))
+	}
+}
+
+// describeBaseline returns the description of the baseline in the same format as describeRun,
+// e.g. "2023-01-01T00:00:00Z (main@0123abc)".
+func describeBaseline(b *benchBaseline) string {
+	return describeRun(historyRun{Started: b.Saved, Commit: b.Commit, Branch: b.Branch})
+}
+
+// formatBenchCenter returns the median with about four significant digits, e.g. "1234", "12.35" or "0.1235".
+func formatBenchCenter(v float64) string {
+	switch a := math.Abs(v); {
+	case a == 0 || a >= 1000:
+		return fmt.Sprintf("%.0f", v)
+	case a >= 100:
+		return fmt.Sprintf("%.1f", v)
+	case a >= 10:
+		return fmt.Sprintf("%.2f", v)
+	case a >= 1:
+		return fmt.Sprintf("%.3f", v)
+	default:
+		return fmt.Sprintf("%.4g", v)
+	}
+}
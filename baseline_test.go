@@ -0,0 +1,181 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/nao1215/hottest/testjson"
+)
+
+func Test_mannWhitneyUTest(t *testing.T) {
+	tests := []struct {
+		name string
+		x    []float64
+		y    []float64
+		want float64
+	}{
+		{
+			name: "Completely separated samples without ties use the exact distribution",
+			x:    []float64{1, 2, 3, 4, 5},
+			y:    []float64{6, 7, 8, 9, 10},
+			want: 2.0 / 252,
+		},
+		{
+			name: "Interleaved samples are not significant",
+			x:    []float64{1, 3, 5, 7, 9},
+			y:    []float64{2, 4, 6, 8, 10},
+			want: 0.6905,
+		},
+		{
+			name: "Tied samples use the normal approximation with the tie correction",
+			x:    []float64{2, 2, 2, 2, 2},
+			y:    []float64{3, 3, 3, 3, 3},
+			want: 0.004,
+		},
+		{
+			name: "Identical samples are not significant",
+			x:    []float64{0, 0, 0},
+			y:    []float64{0, 0, 0},
+			want: 1,
+		},
+		{
+			name: "A single sample is never significant",
+			x:    []float64{1},
+			y:    []float64{100},
+			want: 1,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			if got := mannWhitneyUTest(tt.x, tt.y); math.Abs(got-tt.want) > 0.001 {
+				t.Errorf("mannWhitneyUTest() = %.4f, want %.4f", got, tt.want)
+			}
+		})
+	}
+}
+
+// newBenchmarks returns the samples of a benchmark with the ns/op values.
+func newBenchmarks(name string, nsPerOps ...float64) []*testjson.Benchmark {
+	benchmarks := []*testjson.Benchmark{}
+	for _, v := range nsPerOps {
+		benchmarks = append(benchmarks, &testjson.Benchmark{
+			Package: "example.com/a", Name: name, Procs: 8, Iterations: 1000, NsPerOp: v,
+		})
+	}
+	return benchmarks
+}
+
+func Test_compareBenchmarks(t *testing.T) {
+	before := append(newBenchmarks("BenchmarkA", 100, 101, 102, 103, 104), newBenchmarks("BenchmarkB", 100, 101, 102, 103, 104)...)
+	after := append(newBenchmarks("BenchmarkB", 99, 102, 100, 104, 101), newBenchmarks("BenchmarkA", 120, 121, 122, 123, 124)...)
+	after = append(after, newBenchmarks("BenchmarkNew", 1, 2, 3)...)
+
+	got := compareBenchmarks(before, after)
+	if len(got) != 2 {
+		t.Fatalf("compareBenchmarks() returns %d comparisons, want 2", len(got))
+	}
+
+	t.Run("Compare in the order of the current run and omit the benchmarks not in the baseline", func(t *testing.T) {
+		names := []string{got[0].name, got[1].name}
+		if diff := cmp.Diff([]string{"BenchmarkB-8", "BenchmarkA-8"}, names); diff != "" {
+			t.Errorf("names mismatch (-want +got):\n%s", diff)
+		}
+	})
+
+	t.Run("The noise is not a regression", func(t *testing.T) {
+		if got[0].significant() || got[0].regressed(0) {
+			t.Errorf("BenchmarkB should not be significant: %+v", got[0])
+		}
+	})
+
+	t.Run("The significant delta larger than the threshold is a regression", func(t *testing.T) {
+		if math.Abs(got[1].delta-20.0/102) > 1e-9 {
+			t.Errorf("delta = %f, want %f", got[1].delta, 20.0/102)
+		}
+		if !got[1].regressed(0.1) {
+			t.Errorf("BenchmarkA should regress by more than 10%%: %+v", got[1])
+		}
+		if got[1].regressed(0.3) {
+			t.Errorf("BenchmarkA should not regress by more than 30%%: %+v", got[1])
+		}
+	})
+}
+
+func Test_printBenchComparison(t *testing.T) {
+	baseline := &benchBaseline{}
+
+	t.Run("Warn that the samples are too few to be significant", func(t *testing.T) {
+		comparisons := compareBenchmarks(newBenchmarks("BenchmarkA", 100, 101, 102), newBenchmarks("BenchmarkA", 200, 201, 202))
+		if comparisons[0].significant() || comparisons[0].canBeSignificant() {
+			t.Errorf("n=3+3 should not be significant: %+v", comparisons[0])
+		}
+
+		var b bytes.Buffer
+		printBenchComparison(&b, comparisons, baseline, 0)
+		if want := "warning: 1 of 1 comparisons have too few samples to be significant: run the benchmarks with -count=4 or more"; !strings.Contains(b.String(), want) {
+			t.Errorf("output does not contain %q\n%s", want, b.String())
+		}
+	})
+
+	t.Run("Do not warn if the samples are enough", func(t *testing.T) {
+		comparisons := compareBenchmarks(newBenchmarks("BenchmarkA", 100, 101, 102, 103), newBenchmarks("BenchmarkA", 200, 201, 202, 203))
+		if !comparisons[0].significant() {
+			t.Errorf("n=4+4 should be significant: %+v", comparisons[0])
+		}
+
+		var b bytes.Buffer
+		printBenchComparison(&b, comparisons, baseline, 0)
+		if strings.Contains(b.String(), "warning") {
+			t.Errorf("output should not contain the warning\n%s", b.String())
+		}
+	})
+}
+
+func Test_hottest_compareBenchBaselineIfNeeded(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "baseline.json")
+	h := newHottestFromLog(t, "testdata/bench.json")
+	h.opts.benchBaseline = path
+
+	t.Run("If the tests failed, do not save the baseline", func(t *testing.T) {
+		if err := h.compareBenchBaselineIfNeeded(false); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := os.Stat(path); !errors.Is(err, os.ErrNotExist) {
+			t.Errorf("baseline should not be saved: %v", err)
+		}
+	})
+
+	t.Run("If the baseline does not exist, save the results", func(t *testing.T) {
+		if err := h.compareBenchBaselineIfNeeded(true); err != nil {
+			t.Fatal(err)
+		}
+		baseline, err := loadBenchBaseline(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if diff := cmp.Diff(h.Results.Benchmarks(), baseline.Benchmarks); diff != "" {
+			t.Errorf("baseline mismatch (-want +got):\n%s", diff)
+		}
+	})
+
+	t.Run("If a benchmark regressed by more than the threshold, return error", func(t *testing.T) {
+		if err := saveBenchBaseline(path, newBenchmarks("BenchmarkA", 100, 101, 102, 103, 104)); err != nil {
+			t.Fatal(err)
+		}
+		h := newHottestFromLog(t, "testdata/bench.json")
+		h.opts.benchBaseline = path
+		h.opts.benchThreshold = 0.1
+		h.Results.Packages[0].Benchmarks = newBenchmarks("BenchmarkA", 120, 121, 122, 123, 124)
+
+		if err := h.compareBenchBaselineIfNeeded(true); !errors.Is(err, errBenchRegression) {
+			t.Errorf("compareBenchBaselineIfNeeded() error = %v, want %v", err, errBenchRegression)
+		}
+	})
+}
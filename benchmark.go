@@ -0,0 +1,93 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/go-spectest/markdown"
+	"github.com/nao1215/hottest/testjson"
+)
+
+// benchmarkHeader is the header of the benchmark results table.
+var benchmarkHeader = []string{"BENCHMARK", "PROCS", "ITERATIONS", "NS/OP", "B/OP", "ALLOCS/OP", "METRICS", "PACKAGE"}
+
+// benchmarkRows returns the cells of the benchmark results table.
+func benchmarkRows(benchmarks []*testjson.Benchmark) [][]string {
+	rows := make([][]string, 0, len(benchmarks))
+	for _, b := range benchmarks {
+		bytes, allocs := "-", "-"
+		if b.HasMemory {
+			bytes, allocs = strconv.FormatInt(b.BytesPerOp, 10), strconv.FormatInt(b.AllocsPerOp, 10)
+		}
+		rows = append(rows, []string{
+			b.Label() + b.Name,
+			strconv.Itoa(b.Procs),
+			strconv.FormatInt(b.Iterations, 10),
+			formatBenchmarkValue(b.NsPerOp),
+			bytes,
+			allocs,
+			benchmarkMetrics(b.Metrics),
+			b.Package,
+		})
+	}
+	return rows
+}
+
+// formatBenchmarkValue returns the value of the benchmark metric as go test prints it, e.g. "1234" or "3.55".
+func formatBenchmarkValue(v float64) string {
+	return strconv.FormatFloat(v, 'f', -1, 64)
+}
+
+// benchmarkMetrics returns the custom metrics sorted by unit, e.g. "3.5 items/op, 120 MB/s".
+// It returns "-" if there are no custom metrics.
+func benchmarkMetrics(metrics map[string]float64) string {
+	if len(metrics) == 0 {
+		return "-"
+	}
+	units := make([]string, 0, len(metrics))
+	for unit := range metrics {
+		units = append(units, unit)
+	}
+	sort.Strings(units)
+
+	values := make([]string, 0, len(units))
+	for _, unit := range units {
+		values = append(values, formatBenchmarkValue(metrics[unit])+" "+unit)
+	}
+	return strings.Join(values, ", ")
+}
+
+// printBenchmarks prints the benchmark results as an aligned table.
+// The name and the package are left-aligned, and the numbers are right-aligned.
+func printBenchmarks(w io.Writer, benchmarks []*testjson.Benchmark) {
+	if len(benchmarks) == 0 {
+		return
+	}
+	rows := benchmarkRows(benchmarks)
+
+	widths := make([]int, len(benchmarkHeader))
+	for _, row := range append([][]string{benchmarkHeader}, rows...) {
+		for i, cell := range row {
+			if len(cell) > widths[i] {
+				widths[i] = len(cell)
+			}
+		}
+	}
+
+	fmt.Fprintf(w, "[Benchmarks]\n")
+	for _, row := range append([][]string{benchmarkHeader}, rows...) {
+		fmt.Fprintf(w, " %-*s", widths[0], row[0])
+		for i := 1; i < len(row)-2; i++ {
+			fmt.Fprintf(w, "  %*s", widths[i], row[i])
+		}
+		fmt.Fprintf(w, "  %-*s  %s\n", widths[len(row)-2], row[len(row)-2], row[len(row)-1])
+	}
+}
+
+// benchmarkTable returns the benchmark results table of the markdown report.
+func benchmarkTable(benchmarks []*testjson.Benchmark) markdown.TableSet {
+	return markdown.TableSet{Header: benchmarkHeader, Rows: benchmarkRows(benchmarks)}
+}
@@ -0,0 +1,57 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/nao1215/hottest/testjson"
+)
+
+func Test_printBenchmarks(t *testing.T) {
+	t.Run("Print the aligned table of the benchmark results", func(t *testing.T) {
+		benchmarks := []*testjson.Benchmark{
+			{Package: "example.com/a", Name: "BenchmarkSum", Procs: 8, Iterations: 1000, NsPerOp: 1234, HasMemory: true, BytesPerOp: 56, AllocsPerOp: 2},
+			{Source: "linux", Package: "example.com/a", Name: "BenchmarkSub/n=1", Procs: 1, Iterations: 100, NsPerOp: 3.55,
+				Metrics: map[string]float64{"items/op": 3.5, "MB/s": 120}},
+		}
+
+		var b strings.Builder
+		printBenchmarks(&b, benchmarks)
+		want := "[Benchmarks]\n" +
+			" BENCHMARK                 PROCS  ITERATIONS  NS/OP  B/OP  ALLOCS/OP  METRICS                 PACKAGE\n" +
+			" BenchmarkSum                  8        1000   1234    56          2  -                       example.com/a\n" +
+			" [linux] BenchmarkSub/n=1      1         100   3.55     -          -  120 MB/s, 3.5 items/op  example.com/a\n"
+		if diff := cmp.Diff(want, b.String()); diff != "" {
+			t.Errorf("printBenchmarks() mismatch (-want +got):\n%s", diff)
+		}
+	})
+
+	t.Run("Print nothing if there are no benchmarks", func(t *testing.T) {
+		var b strings.Builder
+		printBenchmarks(&b, nil)
+		if b.Len() != 0 {
+			t.Errorf("printBenchmarks() prints %q, want nothing", b.String())
+		}
+	})
+}
+
+func Test_writeMarkdown_benchmarks(t *testing.T) {
+	t.Run("Write the benchmark results table", func(t *testing.T) {
+		h := newHottestFromLog(t, "testdata/bench.json")
+
+		var b strings.Builder
+		if err := h.writeMarkdown(&b); err != nil {
+			t.Fatal(err)
+		}
+		for _, want := range []string{
+			"### Benchmarks",
+			"| BenchmarkSum     |     2 |        100 |  5.79 |    0 |         0 | -            | example.com/bench |",
+			"| BenchmarkSub/n=1 |     1 |        100 | 12.87 | -    | -         | 3.5 items/op | example.com/bench |",
+		} {
+			if !strings.Contains(b.String(), want) {
+				t.Errorf("markdown does not contain %q\n%s", want, b.String())
+			}
+		}
+	})
+}
@@ -0,0 +1,163 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// defaultBaseRef is the default git ref that -hottest.changed compares with.
+const defaultBaseRef = "main"
+
+// changedFiles returns the absolute paths of the files that differ from the merge base of the base ref and HEAD.
+// The changes made in the base ref after the branch point are not included. Both committed and
+// uncommitted changes are included, and so are the untracked files that are not ignored.
+func changedFiles(baseRef string) ([]string, error) {
+	out, err := exec.Command("git", "rev-parse", "--show-toplevel").Output()
+	if err != nil {
+		return nil, errors.New("-hottest.changed requires git repository")
+	}
+	root := strings.TrimSpace(string(out))
+
+	mergeBase, err := runGit(root, "merge-base", baseRef, "HEAD")
+	if err != nil {
+		return nil, fmt.Errorf("failed to find the merge base of %s and HEAD: %w", baseRef, err)
+	}
+	diff, err := runGit(root, "diff", "--name-only", strings.TrimSpace(mergeBase))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get changed files from %s: %w", baseRef, err)
+	}
+	untracked, err := runGit(root, "ls-files", "--others", "--exclude-standard")
+	if err != nil {
+		return nil, fmt.Errorf("failed to get untracked files: %w", err)
+	}
+
+	files := []string{}
+	for _, f := range strings.Split(diff+"\n"+untracked, "\n") {
+		if strings.TrimSpace(f) == "" {
+			continue
+		}
+		files = append(files, filepath.Join(root, filepath.FromSlash(f)))
+	}
+	return files, nil
+}
+
+// runGit runs the git command in the directory and returns its output.
+// If the command fails, the error has the message that git printed to stderr.
+func runGit(dir string, args ...string) (string, error) {
+	cmd := exec.Command("git", args...) //#nosec
+	cmd.Dir = dir
+	out, err := cmd.Output()
+	if err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			return "", errors.New(strings.TrimSpace(string(exitErr.Stderr)))
+		}
+		return "", err
+	}
+	return string(out), nil
+}
+
+// requiresAllPackages returns true if the change affects all packages,
+// e.g. the module dependencies are changed.
+func requiresAllPackages(files []string) bool {
+	for _, f := range files {
+		switch filepath.Base(f) {
+		case "go.mod", "go.sum", "go.work", "go.work.sum":
+			return true
+		}
+	}
+	return false
+}
+
+// affectedPackages returns the import paths of the packages whose tests are affected by the changed files.
+// A package is affected if the package itself, one of its dependencies or one of the dependencies of
+// its tests contains a changed file. A file in a subdirectory that is not a package (e.g. testdata)
+// belongs to the nearest parent package. Only the packages that have tests are returned.
+func affectedPackages(pkgs []goPackage, files []string) []string {
+	dirs := map[string]string{}
+	for _, p := range pkgs {
+		dirs[filepath.Clean(p.Dir)] = p.ImportPath
+	}
+
+	changed := map[string]bool{}
+	for _, f := range files {
+		for dir := filepath.Dir(f); ; dir = filepath.Dir(dir) {
+			if pkg, ok := dirs[dir]; ok {
+				changed[pkg] = true
+				break
+			}
+			if dir == filepath.Dir(dir) {
+				break
+			}
+		}
+	}
+
+	deps := map[string][]string{}
+	for _, p := range pkgs {
+		deps[p.ImportPath] = p.Deps
+	}
+	// dependsOnChange returns true if the package or one of its dependencies is changed.
+	dependsOnChange := func(pkg string) bool {
+		if changed[pkg] {
+			return true
+		}
+		for _, d := range deps[pkg] {
+			if changed[d] {
+				return true
+			}
+		}
+		return false
+	}
+
+	affected := []string{}
+	for _, p := range pkgs {
+		if !p.hasTests() {
+			continue
+		}
+		isAffected := dependsOnChange(p.ImportPath)
+		for _, imp := range append(append([]string{}, p.TestImports...), p.XTestImports...) {
+			if isAffected {
+				break
+			}
+			isAffected = dependsOnChange(imp)
+		}
+		if isAffected {
+			affected = append(affected, p.ImportPath)
+		}
+	}
+	return affected
+}
+
+// selectChangedPackages replaces the package arguments with the packages affected by
+// the changes from the base ref. If the module dependencies are changed, the package
+// arguments are not replaced. It returns false if no package is affected.
+func selectChangedPackages(args []string, baseRef string) ([]string, bool, error) {
+	flags, patterns := splitPackageArgs(args)
+	if len(patterns) == 0 {
+		patterns = []string{"./..."}
+	}
+
+	files, err := changedFiles(baseRef)
+	if err != nil {
+		return nil, false, err
+	}
+	if requiresAllPackages(files) {
+		fmt.Fprintf(os.Stdout, "module files are changed from %s: test all packages\n", baseRef)
+		return joinPackageArgs(flags, patterns), true, nil
+	}
+
+	pkgs, err := goListPackages(flags, patterns)
+	if err != nil {
+		return nil, false, err
+	}
+	affected := affectedPackages(pkgs, files)
+	if len(affected) == 0 {
+		return nil, false, nil
+	}
+	fmt.Fprintf(os.Stdout, "%d packages are affected by the changes from %s\n", len(affected), baseRef)
+	return joinPackageArgs(flags, affected), true, nil
+}
@@ -0,0 +1,140 @@
+package main
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func Test_affectedPackages(t *testing.T) {
+	root := filepath.FromSlash("/src/example")
+	pkgs := []goPackage{
+		{ImportPath: "example/a", Dir: filepath.Join(root, "a"), TestGoFiles: []string{"a_test.go"}},
+		{ImportPath: "example/b", Dir: filepath.Join(root, "b"), Deps: []string{"example/a"}, TestGoFiles: []string{"b_test.go"}},
+		{ImportPath: "example/c", Dir: filepath.Join(root, "c"), XTestGoFiles: []string{"c_test.go"}, XTestImports: []string{"example/b"}},
+		{ImportPath: "example/d", Dir: filepath.Join(root, "d"), TestGoFiles: []string{"d_test.go"}},
+		{ImportPath: "example/e", Dir: filepath.Join(root, "e"), Deps: []string{"example/a"}},
+	}
+
+	tests := []struct {
+		name  string
+		files []string
+		want  []string
+	}{
+		{
+			name:  "Select the changed package and its reverse dependencies including test imports",
+			files: []string{filepath.Join(root, "a", "a.go")},
+			want:  []string{"example/a", "example/b", "example/c"},
+		},
+		{
+			name:  "Map a file in testdata to the parent package",
+			files: []string{filepath.Join(root, "d", "testdata", "golden.txt")},
+			want:  []string{"example/d"},
+		},
+		{
+			name:  "If the changed files do not belong to any package, select nothing",
+			files: []string{filepath.Join(root, "README.md")},
+			want:  []string{},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			got := affectedPackages(pkgs, tt.files)
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("affectedPackages() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func Test_requiresAllPackages(t *testing.T) {
+	if !requiresAllPackages([]string{"a/a.go", "go.sum"}) {
+		t.Error("requiresAllPackages() should return true if go.sum is changed")
+	}
+	if requiresAllPackages([]string{"a/a.go", "README.md"}) {
+		t.Error("requiresAllPackages() should return false if module files are not changed")
+	}
+}
+
+func Test_changedFiles(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+
+	dir := t.TempDir()
+	t.Setenv("GIT_CONFIG_GLOBAL", os.DevNull)
+	t.Setenv("GIT_CONFIG_NOSYSTEM", "1")
+	git := func(args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", append([]string{"-c", "user.name=test", "-c", "user.email=test@example.com"}, args...)...)
+		cmd.Dir = dir
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %s: %s", args, err, out)
+		}
+	}
+	write := func(name string) {
+		t.Helper()
+		if err := os.MkdirAll(filepath.Join(dir, filepath.Dir(name)), 0o750); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(name), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	git("init", "-q", "-b", "main")
+	write("a/a.go")
+	write(".gitignore")
+	git("add", "-A")
+	git("commit", "-q", "-m", "initial")
+	git("checkout", "-q", "-b", "feature")
+	write("b/b.go")
+	git("add", "-A")
+	git("commit", "-q", "-m", "feature")
+	git("checkout", "-q", "main")
+	write("c/c.go")
+	git("add", "-A")
+	git("commit", "-q", "-m", "main")
+	git("checkout", "-q", "feature")
+	write("a/a.go~")
+	write("d/d.go")
+	if err := os.WriteFile(filepath.Join(dir, ".gitignore"), []byte("*~\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir(filepath.Join(dir, "a")); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Chdir(wd) //nolint
+
+	root, err := filepath.EvalSymlinks(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := changedFiles("main")
+	if err != nil {
+		t.Fatal(err)
+	}
+	sort.Strings(got)
+	want := []string{
+		filepath.Join(root, ".gitignore"),
+		filepath.Join(root, "b", "b.go"),
+		filepath.Join(root, "d", "d.go"),
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("changedFiles() mismatch (-want +got):\n%s", diff)
+	}
+
+	if _, err := changedFiles("not_exist"); err == nil {
+		t.Error("changedFiles() should return error if the base ref does not exist")
+	}
+}
@@ -0,0 +1,265 @@
+package main
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/nao1215/hottest/testjson"
+)
+
+const (
+	// markdownReportFile is the file name of the markdown report.
+	markdownReportFile = "hottest_report.md"
+	// codeQualityReportFile is the file name of the GitLab Code Quality report.
+	codeQualityReportFile = "hottest_codequality.json"
+)
+
+// ciProvider is a CI service that hottest reports the test results to.
+type ciProvider interface {
+	// name returns the name of the CI service.
+	name() string
+	// report outputs the test results in the format native to the CI service.
+	report(h *hottest) error
+}
+
+// detectCIProvider returns the CI service that hottest runs on.
+// The CI service is detected from the environment variables that it sets.
+// If hottest does not run on the supported CI services, it returns nil.
+func detectCIProvider() ciProvider {
+	switch {
+	case os.Getenv("GITHUB_ACTIONS") == "true":
+		return githubActions{}
+	case os.Getenv("GITLAB_CI") == "true":
+		return gitLabCI{}
+	case os.Getenv("CIRCLECI") == "true":
+		return circleCI{}
+	case os.Getenv("BUILDKITE") == "true":
+		return buildkite{}
+	case os.Getenv("JENKINS_URL") != "":
+		return jenkins{}
+	case strings.EqualFold(os.Getenv("TF_BUILD"), "true"):
+		return azurePipelines{}
+	case os.Getenv("TEAMCITY_VERSION") != "":
+		return teamCity{}
+	}
+	return nil
+}
+
+// reportToCI outputs the test results in the format native to the CI service.
+func (h *hottest) reportToCI() {
+	ci := detectCIProvider()
+	if ci == nil {
+		return
+	}
+	if err := ci.report(h); err != nil {
+		fmt.Fprintf(os.Stderr, "failed to report test results to %s: %s\n", ci.name(), err.Error())
+	}
+}
+
+// githubActions is GitHub Actions. It generates the markdown report that
+// nao1215/actions-hottest posts as a PR comment.
+type githubActions struct{}
+
+// name returns the name of the CI service.
+func (githubActions) name() string { return "GitHub Actions" }
+
+// report generates the markdown report.
+func (githubActions) report(h *hottest) error {
+	return h.writeMarkdownFile(markdownReportFile)
+}
+
+// gitLabCI is GitLab CI/CD. It generates the JUnit XML report and the Code Quality report
+// that are specified in artifacts:reports:junit and artifacts:reports:codequality.
+type gitLabCI struct{}
+
+// name returns the name of the CI service.
+func (gitLabCI) name() string { return "GitLab CI" }
+
+// report generates the JUnit XML report and the Code Quality report.
+func (gitLabCI) report(h *hottest) error {
+	if err := h.writeJUnitFile(); err != nil {
+		return err
+	}
+	return h.writeCodeQualityFile()
+}
+
+// circleCI is CircleCI. It generates the JUnit XML report for store_test_results.
+type circleCI struct{}
+
+// name returns the name of the CI service.
+func (circleCI) name() string { return "CircleCI" }
+
+// report generates the JUnit XML report.
+func (circleCI) report(h *hottest) error {
+	return h.writeJUnitFile()
+}
+
+// jenkins is Jenkins. It generates the JUnit XML report for the junit step.
+type jenkins struct{}
+
+// name returns the name of the CI service.
+func (jenkins) name() string { return "Jenkins" }
+
+// report generates the JUnit XML report.
+func (jenkins) report(h *hottest) error {
+	return h.writeJUnitFile()
+}
+
+// buildkite is Buildkite. It annotates the build with the markdown report.
+type buildkite struct{}
+
+// name returns the name of the CI service.
+func (buildkite) name() string { return "Buildkite" }
+
+// report annotates the build with the markdown report by buildkite-agent.
+func (buildkite) report(h *hottest) error {
+	var b bytes.Buffer
+	if err := h.writeMarkdown(&b); err != nil {
+		return err
+	}
+
+	style := "success"
+	if h.Stats.Fail > 0 {
+		style = "error"
+	}
+	cmd := exec.Command("buildkite-agent", "annotate", "--style", style, "--context", "hottest") //#nosec
+	cmd.Stdin = &b
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	return cmd.Run()
+}
+
+// azurePipelines is Azure Pipelines. It logs the failed tests as errors with
+// the logging commands and publishes the JUnit XML report.
+type azurePipelines struct{}
+
+// name returns the name of the CI service.
+func (azurePipelines) name() string { return "Azure Pipelines" }
+
+// report prints the logging commands.
+func (azurePipelines) report(h *hottest) error {
+	writeAzureLoggingCommands(os.Stdout, h.Results, packageDirs{})
+
+	if err := h.writeJUnitFile(); err != nil {
+		return err
+	}
+	path, err := filepath.Abs(junitReportFile)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(os.Stdout, "##vso[results.publish type=JUnit;resultFiles=%s;testRunTitle=hottest]\n",
+		azureEscapeProperty(path))
+	return nil
+}
+
+// writeAzureLoggingCommands writes a task.logissue logging command for each failed test.
+func writeAzureLoggingCommands(w io.Writer, r *testjson.Results, dirs packageDirs) {
+	for _, f := range r.Failures() {
+		msgs := f.Messages()
+		props := []string{"type=error"}
+		if loc, ok := findLocation(msgs); ok {
+			props = append(props,
+				"sourcepath="+azureEscapeProperty(dirs.relPath(f.Package, loc.File)),
+				fmt.Sprintf("linenumber=%d", loc.Line))
+		}
+		fmt.Fprintf(w, "##vso[task.logissue %s;]%s\n", strings.Join(props, ";"),
+			azureEscapeMessage(fmt.Sprintf("%s %s: %s", f.Package, f.Name, failureSummary(msgs))))
+	}
+}
+
+// azureEscapeMessage escapes the message of the Azure Pipelines logging command.
+func azureEscapeMessage(s string) string {
+	return strings.NewReplacer("%", "%AZP25", "\r", "%0D", "\n", "%0A").Replace(s)
+}
+
+// azureEscapeProperty escapes the property value of the Azure Pipelines logging command.
+func azureEscapeProperty(s string) string {
+	return strings.NewReplacer("%", "%AZP25", "\r", "%0D", "\n", "%0A", "]", "%5D", ";", "%3B").Replace(s)
+}
+
+// teamCity is TeamCity. The test results are printed as service messages in real time
+// because hottest uses the teamcity format on TeamCity by default. If another format is
+// specified, the service messages are printed after the run.
+type teamCity struct{}
+
+// name returns the name of the CI service.
+func (teamCity) name() string { return "TeamCity" }
+
+// report prints the service messages of the results unless they have been printed in real time.
+func (teamCity) report(h *hottest) error {
+	if h.opts.format == formatTeamCity {
+		return nil
+	}
+	writeTeamCityResults(os.Stdout, h.Results)
+	return nil
+}
+
+// codeQualityIssue is an issue in the GitLab Code Quality report.
+type codeQualityIssue struct {
+	Description string              `json:"description"`
+	CheckName   string              `json:"check_name"`
+	Fingerprint string              `json:"fingerprint"`
+	Severity    string              `json:"severity"`
+	Location    codeQualityLocation `json:"location"`
+}
+
+// codeQualityLocation is the location of the issue in the GitLab Code Quality report.
+type codeQualityLocation struct {
+	Path  string           `json:"path"`
+	Lines codeQualityLines `json:"lines"`
+}
+
+// codeQualityLines is the lines of the issue in the GitLab Code Quality report.
+type codeQualityLines struct {
+	Begin int `json:"begin"`
+}
+
+// writeCodeQuality writes the failed tests as a GitLab Code Quality report to w.
+// The failed tests without location are not reported because GitLab requires the location.
+func writeCodeQuality(w io.Writer, r *testjson.Results, dirs packageDirs) error {
+	issues := []codeQualityIssue{}
+	for _, f := range r.Failures() {
+		msgs := f.Messages()
+		loc, ok := findLocation(msgs)
+		if !ok {
+			continue
+		}
+		sum := sha256.Sum256([]byte(f.Package + " " + f.Name))
+		issues = append(issues, codeQualityIssue{
+			Description: fmt.Sprintf("%s failed: %s", f.Name, failureSummary(msgs)),
+			CheckName:   "hottest",
+			Fingerprint: hex.EncodeToString(sum[:]),
+			Severity:    "major",
+			Location: codeQualityLocation{
+				Path:  dirs.relPath(f.Package, loc.File),
+				Lines: codeQualityLines{Begin: loc.Line},
+			},
+		})
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(issues)
+}
+
+// writeCodeQualityFile writes the GitLab Code Quality report to codeQualityReportFile.
+func (h *hottest) writeCodeQualityFile() error {
+	f, err := os.Create(codeQualityReportFile)
+	if err != nil {
+		return fmt.Errorf("failed to create %s: %w", codeQualityReportFile, err)
+	}
+	defer f.Close() //nolint
+
+	if err := writeCodeQuality(f, h.Results, packageDirs{}); err != nil {
+		return fmt.Errorf("failed to write %s: %w", codeQualityReportFile, err)
+	}
+	return f.Close()
+}
@@ -0,0 +1,81 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func Test_detectCIProvider(t *testing.T) {
+	tests := []struct {
+		name string
+		env  map[string]string
+		want string
+	}{
+		{name: "If hottest does not run on CI, return nil", env: map[string]string{}, want: ""},
+		{name: "Detect GitHub Actions", env: map[string]string{"GITHUB_ACTIONS": "true"}, want: "GitHub Actions"},
+		{name: "Detect GitLab CI", env: map[string]string{"GITLAB_CI": "true"}, want: "GitLab CI"},
+		{name: "Detect CircleCI", env: map[string]string{"CIRCLECI": "true"}, want: "CircleCI"},
+		{name: "Detect Buildkite", env: map[string]string{"BUILDKITE": "true"}, want: "Buildkite"},
+		{name: "Detect Jenkins", env: map[string]string{"JENKINS_URL": "http://localhost:8080/"}, want: "Jenkins"},
+		{name: "Detect Azure Pipelines", env: map[string]string{"TF_BUILD": "True"}, want: "Azure Pipelines"},
+		{name: "Detect TeamCity", env: map[string]string{"TEAMCITY_VERSION": "2023.05"}, want: "TeamCity"},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			for _, key := range []string{"GITHUB_ACTIONS", "GITLAB_CI", "CIRCLECI", "BUILDKITE", "JENKINS_URL", "TF_BUILD", "TEAMCITY_VERSION"} {
+				t.Setenv(key, tt.env[key])
+			}
+
+			got := ""
+			if ci := detectCIProvider(); ci != nil {
+				got = ci.name()
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("detectCIProvider() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func Test_writeAzureLoggingCommands(t *testing.T) {
+	t.Run("Write logissue command for each failed test", func(t *testing.T) {
+		h := newHottestFromLog(t, "testdata/sample.json")
+		dirs := packageDirs{"example.com/sample/a": ""}
+
+		var b bytes.Buffer
+		writeAzureLoggingCommands(&b, h.Results, dirs)
+
+		want := "##vso[task.logissue type=error;sourcepath=a_test.go;linenumber=10;]example.com/sample/a TestFail/sub_ng: a_test.go:10: got 1, want 2\n"
+		if diff := cmp.Diff(want, b.String()); diff != "" {
+			t.Errorf("writeAzureLoggingCommands() mismatch (-want +got):\n%s", diff)
+		}
+	})
+}
+
+func Test_writeCodeQuality(t *testing.T) {
+	t.Run("Write failed tests as code quality issues", func(t *testing.T) {
+		h := newHottestFromLog(t, "testdata/sample.json")
+		dirs := packageDirs{"example.com/sample/a": ""}
+
+		var b bytes.Buffer
+		if err := writeCodeQuality(&b, h.Results, dirs); err != nil {
+			t.Fatal(err)
+		}
+
+		issues := []codeQualityIssue{}
+		if err := json.Unmarshal(b.Bytes(), &issues); err != nil {
+			t.Fatal(err)
+		}
+		if len(issues) != 1 {
+			t.Fatalf("got %d issues, want 1", len(issues))
+		}
+		want := codeQualityLocation{Path: "a_test.go", Lines: codeQualityLines{Begin: 10}}
+		if diff := cmp.Diff(want, issues[0].Location); diff != "" {
+			t.Errorf("location mismatch (-want +got):\n%s", diff)
+		}
+	})
+}
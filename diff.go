@@ -0,0 +1,146 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"math"
+	"os"
+
+	"github.com/fatih/color"
+	"github.com/nao1215/hottest/testjson"
+)
+
+const (
+	// defaultDiffThreshold is the default rate of change of the elapsed time
+	// that is regarded as a duration change.
+	defaultDiffThreshold = 0.5
+)
+
+// testStatusChange is a test whose result differs between two runs.
+type testStatusChange struct {
+	before historyTest
+	after  historyTest
+}
+
+// runDiff is the difference of the test results between two runs.
+type runDiff struct {
+	changed         []testStatusChange
+	appeared        []historyTest
+	disappeared     []historyTest
+	durationChanged []testStatusChange
+}
+
+// diffRuns compares the test results of two runs. The elapsed time is regarded as changed
+// if the rate of change exceeds threshold and the difference is minRegressionDiff or more.
+func diffRuns(before, after []historyTest, threshold float64) runDiff {
+	d := runDiff{}
+
+	beforeTests := map[string]historyTest{}
+	for _, t := range before {
+		beforeTests[t.key()] = t
+	}
+	afterTests := map[string]historyTest{}
+	for _, t := range after {
+		afterTests[t.key()] = t
+	}
+
+	for _, a := range after {
+		b, ok := beforeTests[a.key()]
+		if !ok {
+			d.appeared = append(d.appeared, a)
+			continue
+		}
+		if a.Action != b.Action {
+			d.changed = append(d.changed, testStatusChange{before: b, after: a})
+			continue
+		}
+		diff := math.Abs(a.Elapsed - b.Elapsed)
+		if diff >= minRegressionDiff && (b.Elapsed == 0 || diff/b.Elapsed > threshold) {
+			d.durationChanged = append(d.durationChanged, testStatusChange{before: b, after: a})
+		}
+	}
+	for _, b := range before {
+		if _, ok := afterTests[b.key()]; !ok {
+			d.disappeared = append(d.disappeared, b)
+		}
+	}
+	return d
+}
+
+// print prints the difference.
+func (d runDiff) print(w io.Writer) {
+	fmt.Fprintf(w, "[Changed status]\n")
+	if len(d.changed) == 0 {
+		fmt.Fprintf(w, " none\n")
+	}
+	for _, c := range d.changed {
+		fmt.Fprintf(w, " %s %s: %s -> %s\n", c.after.Name, c.after.Package, colorAction(c.before.Action), colorAction(c.after.Action))
+	}
+
+	fmt.Fprintf(w, "[New tests]\n")
+	if len(d.appeared) == 0 {
+		fmt.Fprintf(w, " none\n")
+	}
+	for _, t := range d.appeared {
+		fmt.Fprintf(w, " %s %s (%s)\n", t.Name, t.Package, colorAction(t.Action))
+	}
+
+	fmt.Fprintf(w, "[Removed tests]\n")
+	if len(d.disappeared) == 0 {
+		fmt.Fprintf(w, " none\n")
+	}
+	for _, t := range d.disappeared {
+		fmt.Fprintf(w, " %s %s (%s)\n", t.Name, t.Package, colorAction(t.Action))
+	}
+
+	fmt.Fprintf(w, "[Duration changes]\n")
+	if len(d.durationChanged) == 0 {
+		fmt.Fprintf(w, " none\n")
+	}
+	for _, c := range d.durationChanged {
+		fmt.Fprintf(w, " %s %s %s -> %s%s\n", c.after.Name, c.after.Package,
+			elapsedString(c.before.Elapsed), elapsedString(c.after.Elapsed), changeRate(c.before.Elapsed, c.after.Elapsed))
+	}
+}
+
+// colorAction returns the colored action of the test.
+func colorAction(action string) string {
+	switch action {
+	case testjson.ActionPass:
+		return color.GreenString(action)
+	case testjson.ActionFail:
+		return color.RedString(action)
+	case testjson.ActionSkip:
+		return color.BlueString(action)
+	}
+	return action
+}
+
+// runDiffCommand runs the 'hottest diff' subcommand.
+func runDiffCommand(args []string) error {
+	fs := flag.NewFlagSet("diff", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	threshold := fs.Float64("threshold", defaultDiffThreshold,
+		"rate of change of the elapsed time regarded as a duration change")
+	if err := fs.Parse(args); err != nil {
+		return fmt.Errorf("%w: %s", errInvalidOption, err.Error())
+	}
+	if fs.NArg() != 2 {
+		return fmt.Errorf("%w: 'hottest diff' requires two 'go test -json' log files", errInvalidOption)
+	}
+
+	before, err := readLog(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	after, err := readLog(fs.Arg(1))
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(os.Stdout, "Before: %s %s\n", fs.Arg(0), before.statsString())
+	fmt.Fprintf(os.Stdout, "After:  %s %s\n", fs.Arg(1), after.statsString())
+	diffRuns(historyTests(before.Results), historyTests(after.Results), *threshold).print(os.Stdout)
+	return nil
+}
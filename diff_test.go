@@ -0,0 +1,70 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/fatih/color"
+	"github.com/google/go-cmp/cmp"
+	"github.com/nao1215/hottest/testjson"
+)
+
+func Test_diffRuns(t *testing.T) {
+	test := func(name, action string, elapsed float64) historyTest {
+		return historyTest{Package: "p", Name: name, Action: action, Elapsed: elapsed}
+	}
+	before := []historyTest{
+		test("TestBroken", testjson.ActionPass, 0),
+		test("TestRemoved", testjson.ActionPass, 0),
+		test("TestSlow", testjson.ActionPass, 0.2),
+		test("TestFast", testjson.ActionPass, 1),
+		test("TestStable", testjson.ActionPass, 1),
+	}
+	after := []historyTest{
+		test("TestBroken", testjson.ActionFail, 0),
+		test("TestSlow", testjson.ActionPass, 1),
+		test("TestFast", testjson.ActionPass, 0.2),
+		test("TestStable", testjson.ActionPass, 1.05),
+		test("TestAdded", testjson.ActionSkip, 0),
+	}
+
+	d := diffRuns(before, after, defaultDiffThreshold)
+
+	t.Run("Find tests that changed status, appeared and disappeared", func(t *testing.T) {
+		want := runDiff{
+			changed:     []testStatusChange{{before: before[0], after: after[0]}},
+			appeared:    []historyTest{after[4]},
+			disappeared: []historyTest{before[1]},
+			durationChanged: []testStatusChange{
+				{before: before[2], after: after[1]},
+				{before: before[3], after: after[2]},
+			},
+		}
+		if diff := cmp.Diff(want, d, cmp.AllowUnexported(runDiff{}, testStatusChange{})); diff != "" {
+			t.Errorf("diffRuns() mismatch (-want +got):\n%s", diff)
+		}
+	})
+
+	t.Run("Print the difference", func(t *testing.T) {
+		noColor := color.NoColor
+		color.NoColor = true
+		defer func() {
+			color.NoColor = noColor
+		}()
+
+		var b bytes.Buffer
+		d.print(&b)
+		for _, want := range []string{
+			" TestBroken p: pass -> fail",
+			" TestAdded p (skip)",
+			" TestRemoved p (pass)",
+			" TestSlow p 200ms -> 1s (+400%)",
+			" TestFast p 1s -> 200ms (-80%)",
+		} {
+			if !strings.Contains(b.String(), want) {
+				t.Errorf("output does not contain %q\n%s", want, b.String())
+			}
+		}
+	})
+}
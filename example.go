@@ -0,0 +1,114 @@
+package main
+
+import (
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/nao1215/hottest/testjson"
+)
+
+// diffLine is a line of the line-by-line diff.
+type diffLine struct {
+	// op is '-' for the expected line, '+' for the actual line and ' ' for the common line.
+	op   byte
+	text string
+}
+
+// String returns the line in the unified diff format, e.g. "- world".
+func (d diffLine) String() string {
+	return string(d.op) + " " + d.text
+}
+
+// colored returns the line that is colored red if it is expected, green if it is actual.
+func (d diffLine) colored() string {
+	switch d.op {
+	case '-':
+		return color.RedString(d.String())
+	case '+':
+		return color.GreenString(d.String())
+	default:
+		return d.String()
+	}
+}
+
+// diffLines returns the line-by-line diff from want to got based on the longest common subsequence.
+func diffLines(want, got []string) []diffLine {
+	// lcs[i][j] is the length of the longest common subsequence of want[i:] and got[j:].
+	lcs := make([][]int, len(want)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(got)+1)
+	}
+	for i := len(want) - 1; i >= 0; i-- {
+		for j := len(got) - 1; j >= 0; j-- {
+			switch {
+			case want[i] == got[j]:
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			case lcs[i+1][j] >= lcs[i][j+1]:
+				lcs[i][j] = lcs[i+1][j]
+			default:
+				lcs[i][j] = lcs[i][j+1]
+			}
+		}
+	}
+
+	diff := []diffLine{}
+	i, j := 0, 0
+	for i < len(want) || j < len(got) {
+		switch {
+		case i < len(want) && j < len(got) && want[i] == got[j]:
+			diff = append(diff, diffLine{op: ' ', text: want[i]})
+			i++
+			j++
+		case j >= len(got) || (i < len(want) && lcs[i+1][j] >= lcs[i][j+1]):
+			diff = append(diff, diffLine{op: '-', text: want[i]})
+			i++
+		default:
+			diff = append(diff, diffLine{op: '+', text: got[j]})
+			j++
+		}
+	}
+	return diff
+}
+
+// exampleDiff returns the diff from the expected output to the actual output of the failed example.
+// It returns false if the test is not a failed example or the output cannot be found.
+func exampleDiff(t *testjson.TestResult) ([]diffLine, bool) {
+	if !testjson.IsExample(t.Name) || t.Action != testjson.ActionFail {
+		return nil, false
+	}
+	got, want, ok := testjson.ParseExampleOutput(t.Output)
+	if !ok {
+		return nil, false
+	}
+	return diffLines(want, got), true
+}
+
+// failedExamples returns the failed examples keyed by the label and the name, e.g. "[linux] ExampleX".
+// The examples with the same key in different packages are in the order of appearance.
+func failedExamples(r *testjson.Results) map[string][]*testjson.TestResult {
+	examples := map[string][]*testjson.TestResult{}
+	for _, t := range r.Tests(testjson.ActionFail) {
+		if testjson.IsExample(t.Name) {
+			examples[t.Label()+t.Name] = append(examples[t.Label()+t.Name], t)
+		}
+	}
+	return examples
+}
+
+// failedExampleKey returns the key of failedExamples for the "--- FAIL" line of the error messages,
+// e.g. "[linux] ExampleX" for "[linux] --- FAIL: ExampleX (0.00s)".
+func failedExampleKey(msg string) string {
+	i := strings.Index(msg, "--- FAIL: ")
+	if i < 0 {
+		return ""
+	}
+	label := strings.TrimSpace(msg[:i])
+	if label != "" {
+		label += " "
+	}
+	name := msg[i+len("--- FAIL: "):]
+	if j := strings.Index(name, " ("); j >= 0 {
+		name = name[:j]
+	}
+	return label + name
+}
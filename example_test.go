@@ -0,0 +1,77 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func Test_diffLines(t *testing.T) {
+	tests := []struct {
+		name string
+		want []string
+		got  []string
+		diff []string
+	}{
+		{
+			name: "Show the changed line as the removed and the added lines",
+			want: []string{"hello", "world", "!"},
+			got:  []string{"hello", "wrld", "!"},
+			diff: []string{"  hello", "- world", "+ wrld", "  !"},
+		},
+		{
+			name: "Show the missing and the extra lines",
+			want: []string{"a", "b", "c"},
+			got:  []string{"b", "c", "d"},
+			diff: []string{"- a", "  b", "  c", "+ d"},
+		},
+		{
+			name: "Show the empty actual output",
+			want: []string{"a"},
+			got:  nil,
+			diff: []string{"- a"},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			got := []string{}
+			for _, d := range diffLines(tt.want, tt.got) {
+				got = append(got, d.String())
+			}
+			if diff := cmp.Diff(tt.diff, got); diff != "" {
+				t.Errorf("diffLines() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func Test_hottest_printErrorMessages(t *testing.T) {
+	t.Run("Print the diff of the failed examples after the --- FAIL lines", func(t *testing.T) {
+		h := newHottestFromLog(t, "testdata/example.json")
+
+		var b strings.Builder
+		h.printErrorMessages(&b)
+		want := "[Error Messages]\n" +
+			" --- FAIL: Example_hello (0.00s)\n" +
+			"     (-want +got)\n" +
+			"       hello\n" +
+			"     - world\n" +
+			"     + wrld\n" +
+			"       !\n" +
+			" --- FAIL: Example_empty (0.00s)\n" +
+			"     (-want +got)\n" +
+			"     - something\n" +
+			"     + \n"
+		if diff := cmp.Diff(want, b.String()); diff != "" {
+			t.Errorf("printErrorMessages() mismatch (-want +got):\n%s", diff)
+		}
+	})
+
+	t.Run("Find the failed examples of the merged logs by the label", func(t *testing.T) {
+		if diff := cmp.Diff("[linux] Example_hello", failedExampleKey("[linux] --- FAIL: Example_hello (0.00s)")); diff != "" {
+			t.Errorf("failedExampleKey() mismatch (-want +got):\n%s", diff)
+		}
+	})
+}
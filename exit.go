@@ -0,0 +1,157 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/nao1215/hottest/testjson"
+)
+
+// The exit codes of the hottest command. If the run fails for several reasons,
+// the first one in this order is used: interrupted, go not found, usage error, build failure, timeout and test failure.
+const (
+	// exitCodeOK means that all tests passed.
+	exitCodeOK = 0
+	// exitCodeTestFailure means that a test failed, or the results violate the policy options or the benchmark threshold.
+	exitCodeTestFailure = 1
+	// exitCodeUsage means that the hottest options or the arguments are invalid.
+	exitCodeUsage = 2
+	// exitCodeBuildFailure means that a package failed to build or to set up.
+	exitCodeBuildFailure = 3
+	// exitCodeTimeout means that a test binary exceeded -timeout.
+	exitCodeTimeout = 4
+	// exitCodeGoNotFound means that the go command is not found, as the shell returns for a missing command.
+	exitCodeGoNotFound = 127
+	// exitCodeInterrupted means that the run was interrupted by SIGINT or SIGTERM, as the shell returns for SIGINT.
+	exitCodeInterrupted = 130
+)
+
+// The outcomes of the run that are passed to the reporters. They correspond to the exit codes.
+const (
+	// outcomePass means that all tests passed.
+	outcomePass = "pass"
+	// outcomeFail means that a test failed, or the results violate the policy options or the benchmark threshold.
+	outcomeFail = "fail"
+	// outcomeBuildFailure means that a package failed to build or to set up.
+	outcomeBuildFailure = "build_failure"
+	// outcomeTimeout means that a test binary exceeded -timeout.
+	outcomeTimeout = "timeout"
+	// outcomeInterrupted means that the run was interrupted by SIGINT or SIGTERM.
+	outcomeInterrupted = "interrupted"
+	// outcomeError means that the tests could not be run, e.g. the go command could not be started.
+	outcomeError = "error"
+)
+
+var (
+	// errBuildFailed is an error that occurs when a package fails to build.
+	errBuildFailed = errors.New("build failed")
+	// errTimeout is an error that occurs when a test binary exceeds -timeout.
+	errTimeout = errors.New("test timed out")
+	// errInterrupted is an error that occurs when the run is interrupted by a signal.
+	errInterrupted = errors.New("interrupted")
+	// errGoNotFound is an error that occurs when the go command is not found.
+	errGoNotFound = errors.New("hottest command requires go command. please install go command")
+)
+
+// exitCode returns the exit code of the error returned by run.
+func exitCode(err error) int {
+	switch {
+	case err == nil:
+		return exitCodeOK
+	case errors.Is(err, errInterrupted):
+		return exitCodeInterrupted
+	case errors.Is(err, errGoNotFound):
+		return exitCodeGoNotFound
+	case errors.Is(err, errInvalidOption):
+		return exitCodeUsage
+	case errors.Is(err, errBuildFailed):
+		return exitCodeBuildFailure
+	case errors.Is(err, errTimeout):
+		return exitCodeTimeout
+	default:
+		return exitCodeTestFailure
+	}
+}
+
+// runOutcome returns the outcome of the error returned by run.
+func runOutcome(err error) string {
+	switch exitCode(err) {
+	case exitCodeOK:
+		return outcomePass
+	case exitCodeInterrupted:
+		return outcomeInterrupted
+	case exitCodeBuildFailure:
+		return outcomeBuildFailure
+	case exitCodeTimeout:
+		return outcomeTimeout
+	case exitCodeTestFailure:
+		if errors.Is(err, errFailTest) || errors.Is(err, errExitStatus) ||
+			errors.Is(err, errPolicyViolation) || errors.Is(err, errBenchRegression) {
+			return outcomeFail
+		}
+	}
+	return outcomeError
+}
+
+// testError returns the error of the test run that tells why the run failed.
+// err is the error of the test commands. The build failures and the timeouts are found
+// in the results because go test exits with 1 for any failure.
+func (h *hottest) testError(err error) error {
+	if errors.Is(err, errInterrupted) {
+		return err
+	}
+
+	var built, timedOut []*testjson.PackageResult
+	for _, p := range h.Results.Packages {
+		switch {
+		case p.BuildFailed():
+			built = append(built, p)
+		case p.TimedOut():
+			timedOut = append(timedOut, p)
+		}
+	}
+	if len(built) > 0 {
+		return fmt.Errorf("%w: %s", errBuildFailed, buildFailures(h.Results, built))
+	}
+	if len(timedOut) > 0 {
+		return fmt.Errorf("%w: %s", errTimeout, timeouts(timedOut))
+	}
+
+	if err != nil && !errors.Is(err, errExitStatus) {
+		return err
+	}
+	if h.Stats.Fail > 0 {
+		return errFailTest
+	}
+	return err
+}
+
+// buildFailures returns the packages that failed to build and their build output, e.g.
+// "example.com/a\n# example.com/a\na.go:3:12: undefined: f".
+func buildFailures(r *testjson.Results, pkgs []*testjson.PackageResult) string {
+	names := make([]string, 0, len(pkgs))
+	output := []string{}
+	for _, p := range pkgs {
+		names = append(names, p.Key())
+		output = append(output, r.BuildOutput(p)...)
+	}
+	return strings.Join(append([]string{strings.Join(names, ", ")}, output...), "\n")
+}
+
+// timeouts returns the packages that timed out and the tests that were running, e.g. "TestX (example.com/a)".
+// If the running tests are unknown, only the package is returned.
+func timeouts(pkgs []*testjson.PackageResult) string {
+	names := []string{}
+	for _, p := range pkgs {
+		unfinished := p.Unfinished()
+		if len(unfinished) == 0 {
+			names = append(names, p.Key())
+			continue
+		}
+		for _, t := range unfinished {
+			names = append(names, fmt.Sprintf("%s%s (%s)", t.Label(), t.Name, t.Package))
+		}
+	}
+	return strings.Join(names, ", ")
+}
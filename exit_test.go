@@ -0,0 +1,112 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func Test_exitCode(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want int
+	}{
+		{name: "Success", err: nil, want: exitCodeOK},
+		{name: "Test failure", err: errFailTest, want: exitCodeTestFailure},
+		{name: "Exit status of go test", err: errExitStatus, want: exitCodeTestFailure},
+		{name: "Policy violation", err: fmt.Errorf("%w: no tests ran", errPolicyViolation), want: exitCodeTestFailure},
+		{name: "Usage error", err: fmt.Errorf("%w: unknown format 'xml'", errInvalidOption), want: exitCodeUsage},
+		{name: "Build failure", err: fmt.Errorf("%w: example.com/a", errBuildFailed), want: exitCodeBuildFailure},
+		{name: "Timeout", err: fmt.Errorf("%w: TestX (example.com/a)", errTimeout), want: exitCodeTimeout},
+		{name: "Go not found", err: errGoNotFound, want: exitCodeGoNotFound},
+		{name: "Interrupted", err: errInterrupted, want: exitCodeInterrupted},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			if got := exitCode(tt.err); got != tt.want {
+				t.Errorf("exitCode() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_runOutcome(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want string
+	}{
+		{name: "Success", err: nil, want: outcomePass},
+		{name: "Test failure", err: errFailTest, want: outcomeFail},
+		{name: "Benchmark regression", err: fmt.Errorf("%w: BenchmarkX", errBenchRegression), want: outcomeFail},
+		{name: "Build failure", err: fmt.Errorf("%w: example.com/a", errBuildFailed), want: outcomeBuildFailure},
+		{name: "Timeout", err: fmt.Errorf("%w: TestX (example.com/a)", errTimeout), want: outcomeTimeout},
+		{name: "Interrupted", err: errInterrupted, want: outcomeInterrupted},
+		{name: "Other error", err: errors.New("failed to create log"), want: outcomeError},
+		{name: "Go not found", err: errGoNotFound, want: outcomeError},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			if got := runOutcome(tt.err); got != tt.want {
+				t.Errorf("runOutcome() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_testError(t *testing.T) {
+	tests := []struct {
+		name    string
+		log     string
+		err     error
+		wantErr error
+		wantMsg string
+	}{
+		{
+			name:    "If a package failed to build, return the build output",
+			log:     "testdata/build.json",
+			err:     errExitStatus,
+			wantErr: errBuildFailed,
+			wantMsg: "build failed: example.com/x/bad\n# example.com/x/bad [example.com/x/bad.test]\nbad/bad.go:3:12: undefined: undefined",
+		},
+		{
+			name:    "If a test binary timed out, return the running tests",
+			log:     "testdata/timeout.json",
+			err:     errExitStatus,
+			wantErr: errTimeout,
+			wantMsg: "test timed out: TestSlow (example.com/x/slow)",
+		},
+		{
+			name:    "If a test failed, return errFailTest",
+			log:     "testdata/sample.json",
+			err:     errExitStatus,
+			wantErr: errFailTest,
+		},
+		{
+			name:    "If the run was interrupted, return errInterrupted even if tests failed",
+			log:     "testdata/sample.json",
+			err:     errInterrupted,
+			wantErr: errInterrupted,
+		},
+		{
+			name:    "If all tests passed, return nil",
+			log:     "testdata/skip.json",
+			wantErr: nil,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			err := newHottestFromLog(t, tt.log).testError(tt.err)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("testError() error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantMsg != "" && err.Error() != tt.wantMsg {
+				t.Errorf("testError() error = %q, want %q", err.Error(), tt.wantMsg)
+			}
+		})
+	}
+}
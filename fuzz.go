@@ -0,0 +1,93 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"unicode"
+
+	"github.com/nao1215/hottest/testjson"
+)
+
+// fuzzInputMaxLines is the maximum number of lines of the failing input that are printed.
+const fuzzInputMaxLines = 20
+
+// withFuzzInputs returns the error messages in which the failing inputs of the fuzzing are expanded:
+// the content of the minimized input is inserted after "Failing input written to ...", and the package
+// is added to the command that re-runs the input. The inputs that cannot be read are not inserted.
+func withFuzzInputs(msgs []string, failures []*testjson.FuzzFailure, dirs packageDirs) []string {
+	if len(failures) == 0 {
+		return msgs
+	}
+
+	var current *testjson.FuzzFailure
+	expanded := make([]string, 0, len(msgs))
+	for _, msg := range msgs {
+		indent := msg[:len(msg)-len(strings.TrimLeftFunc(msg, unicode.IsSpace))]
+		if path, ok := testjson.FuzzInputPath(msg); ok {
+			current = findFuzzFailure(failures, path)
+			expanded = append(expanded, msg)
+			if current != nil {
+				for _, line := range readFuzzInput(current, dirs) {
+					expanded = append(expanded, indent+"    "+line)
+				}
+			}
+			continue
+		}
+		if current != nil && strings.HasPrefix(strings.TrimSpace(msg), "go test -run=") {
+			msg = indent + current.RerunCommand()
+			current = nil
+		}
+		expanded = append(expanded, msg)
+	}
+	return expanded
+}
+
+// findFuzzFailure returns the failure whose input is written to the path. It returns nil if there is no such failure.
+func findFuzzFailure(failures []*testjson.FuzzFailure, path string) *testjson.FuzzFailure {
+	for _, f := range failures {
+		if f.InputPath == path {
+			return f
+		}
+	}
+	return nil
+}
+
+// readFuzzInput returns the lines of the failing input file, e.g. "go test fuzz v1" and "string(\"x\")".
+// The lines after fuzzInputMaxLines are omitted. It returns nil if the file cannot be read,
+// e.g. the log is read on another machine.
+func readFuzzInput(f *testjson.FuzzFailure, dirs packageDirs) []string {
+	dir := dirs.dir(f.Package)
+	if dir == "" {
+		return nil
+	}
+	b, err := os.ReadFile(filepath.Join(dir, filepath.FromSlash(f.InputPath)))
+	if err != nil {
+		return nil
+	}
+	lines := strings.Split(strings.TrimRightFunc(string(b), unicode.IsSpace), "\n")
+	if len(lines) > fuzzInputMaxLines {
+		lines = append(lines[:fuzzInputMaxLines], fmt.Sprintf("... (%d more lines)", len(lines)-fuzzInputMaxLines))
+	}
+	return lines
+}
+
+// printFuzz prints the progress of the fuzz targets and the new failing inputs, e.g.
+// "FuzzX (example.com/a): 114123 execs in 4s, 0 new interesting inputs (total: 2)".
+func printFuzz(w io.Writer, r *testjson.Results) {
+	progresses, failures := r.FuzzProgresses(), r.FuzzFailures()
+	if len(progresses) == 0 && len(failures) == 0 {
+		return
+	}
+
+	fmt.Fprintf(w, "[Fuzz]\n")
+	for _, p := range progresses {
+		fmt.Fprintf(w, " %s%s (%s): %d execs in %s, %d new interesting inputs (total: %d)\n",
+			p.Label(), p.Name, p.Package, p.Execs, p.Elapsed, p.NewInteresting, p.TotalInteresting)
+	}
+	for _, f := range failures {
+		fmt.Fprintf(w, " %s%s (%s): failing input written to %s\n", f.Label(), f.Name, f.Package, f.InputPath)
+	}
+}
@@ -0,0 +1,52 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func Test_withFuzzInputs(t *testing.T) {
+	h := newHottestFromLog(t, "testdata/fuzz.json")
+
+	t.Run("Insert the failing input and complete the command to re-run it", func(t *testing.T) {
+		// The failing input of the log is saved in testdata/fuzz.
+		dirs := packageDirs{"example.com/sample/fuzz": "."}
+		got := withFuzzInputs(h.FailMessages(), h.Results.FuzzFailures(), dirs)
+		want := []string{
+			"--- FAIL: FuzzReverse (0.03s)",
+			"    --- FAIL: FuzzReverse (0.00s)",
+			"            f_test.go:3: bad input \"x000\"",
+			"        Failing input written to testdata/fuzz/FuzzReverse/1de061fa29cfbb3d",
+			"            go test fuzz v1",
+			"            string(\"x000\")",
+			"        To re-run:",
+			"        go test -run=FuzzReverse/1de061fa29cfbb3d example.com/sample/fuzz",
+		}
+		if diff := cmp.Diff(want, got); diff != "" {
+			t.Errorf("withFuzzInputs() mismatch (-want +got):\n%s", diff)
+		}
+	})
+
+	t.Run("If the failing input cannot be read, only complete the command", func(t *testing.T) {
+		dirs := packageDirs{"example.com/sample/fuzz": "testdata/not_exist"}
+		got := withFuzzInputs(h.FailMessages(), h.Results.FuzzFailures(), dirs)
+		if len(got) != len(h.FailMessages()) {
+			t.Errorf("withFuzzInputs() returns %d lines, want %d\n%s", len(got), len(h.FailMessages()), strings.Join(got, "\n"))
+		}
+	})
+}
+
+func Test_printFuzz(t *testing.T) {
+	t.Run("Print the progress and the new failing input of the fuzz target", func(t *testing.T) {
+		var b strings.Builder
+		printFuzz(&b, newHottestFromLog(t, "testdata/fuzz.json").Results)
+		want := "[Fuzz]\n" +
+			" FuzzReverse (example.com/sample/fuzz): 84026 execs in 3s, 1 new interesting inputs (total: 2)\n" +
+			" FuzzReverse (example.com/sample/fuzz): failing input written to testdata/fuzz/FuzzReverse/1de061fa29cfbb3d\n"
+		if diff := cmp.Diff(want, b.String()); diff != "" {
+			t.Errorf("printFuzz() mismatch (-want +got):\n%s", diff)
+		}
+	})
+}
@@ -0,0 +1,147 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os/exec"
+	"strings"
+)
+
+// goTestFlagsWithValue is the set of the 'go test' and build flags that take a value
+// as the next argument (e.g. "-run TestX"). It is used to find the package arguments.
+// The test flags may also be specified with the "test." prefix (e.g. "-test.run TestX").
+var goTestFlagsWithValue = map[string]bool{
+	"asmflags": true, "bench": true, "benchtime": true, "blockprofile": true, "blockprofilerate": true,
+	"compiler": true, "count": true, "covermode": true, "coverpkg": true, "coverprofile": true, "cpu": true,
+	"cpuprofile": true, "exec": true, "fuzz": true, "fuzzcachedir": true, "fuzzminimizetime": true, "fuzztime": true,
+	"gccgoflags": true, "gcflags": true, "gocoverdir": true, "installsuffix": true, "ldflags": true, "list": true,
+	"memprofile": true, "memprofilerate": true, "mod": true, "modfile": true, "mutexprofile": true,
+	"mutexprofilefraction": true, "o": true, "outputdir": true, "overlay": true, "p": true,
+	"parallel": true, "pgo": true, "pkgdir": true, "run": true, "shuffle": true, "skip": true,
+	"tags": true, "testlogfile": true, "timeout": true, "toolexec": true, "trace": true, "vet": true, "C": true,
+}
+
+// splitPackageArgs separates the package arguments from the 'go test' flags.
+// The arguments after "-args" are passed to the test binary, so they are regarded as flags.
+func splitPackageArgs(args []string) (flags []string, pkgs []string) {
+	flags = []string{}
+	pkgs = []string{}
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		switch {
+		case arg == "-args" || arg == "--args":
+			flags = append(flags, args[i:]...)
+			return flags, pkgs
+		case strings.HasPrefix(arg, "-"):
+			flags = append(flags, arg)
+			name := strings.TrimLeft(arg, "-")
+			if !strings.Contains(name, "=") && goTestFlagsWithValue[strings.TrimPrefix(name, "test.")] && i+1 < len(args) {
+				i++
+				flags = append(flags, args[i])
+			}
+		default:
+			pkgs = append(pkgs, arg)
+		}
+	}
+	return flags, pkgs
+}
+
+// joinPackageArgs joins the flags and the packages. The packages are placed before
+// "-args" because the arguments after "-args" are passed to the test binary.
+func joinPackageArgs(flags []string, pkgs []string) []string {
+	args := []string{}
+	for i, f := range flags {
+		if f == "-args" || f == "--args" {
+			args = append(args, pkgs...)
+			return append(args, flags[i:]...)
+		}
+		args = append(args, f)
+	}
+	return append(args, pkgs...)
+}
+
+// goPackage is the package information printed by 'go list -json'.
+type goPackage struct {
+	ImportPath   string   `json:"ImportPath"`
+	Dir          string   `json:"Dir"`
+	Deps         []string `json:"Deps"`
+	TestGoFiles  []string `json:"TestGoFiles"`
+	XTestGoFiles []string `json:"XTestGoFiles"`
+	TestImports  []string `json:"TestImports"`
+	XTestImports []string `json:"XTestImports"`
+}
+
+// hasTests returns true if the package has test files.
+func (p goPackage) hasTests() bool {
+	return len(p.TestGoFiles) > 0 || len(p.XTestGoFiles) > 0
+}
+
+// goListPackages returns the packages that match the patterns by 'go list -json'.
+// If patterns is empty, the package in the current directory is returned like 'go test'.
+func goListPackages(flags []string, patterns []string) ([]goPackage, error) {
+	args := append([]string{"list", "-json"}, buildFlags(flags)...)
+	args = append(args, patterns...)
+	cmd := exec.Command("go", args...) //#nosec
+	out, err := cmd.Output()
+	if err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			return nil, fmt.Errorf("failed to list packages: %s", strings.TrimSpace(string(exitErr.Stderr)))
+		}
+		return nil, fmt.Errorf("failed to list packages: %w", err)
+	}
+	return decodeGoPackages(strings.NewReader(string(out)))
+}
+
+// decodeGoPackages decodes the stream of the JSON objects printed by 'go list -json'.
+func decodeGoPackages(r io.Reader) ([]goPackage, error) {
+	pkgs := []goPackage{}
+	dec := json.NewDecoder(r)
+	for {
+		var p goPackage
+		if err := dec.Decode(&p); err != nil {
+			if errors.Is(err, io.EOF) {
+				return pkgs, nil
+			}
+			return nil, fmt.Errorf("failed to decode 'go list' output: %w", err)
+		}
+		pkgs = append(pkgs, p)
+	}
+}
+
+// buildFlags returns the flags that affect which packages and files 'go list' selects.
+func buildFlags(flags []string) []string {
+	build := []string{}
+	for i := 0; i < len(flags); i++ {
+		name := strings.SplitN(strings.TrimLeft(flags[i], "-"), "=", 2)[0]
+		switch name {
+		case "tags", "mod", "modfile":
+			build = append(build, flags[i])
+			if !strings.Contains(flags[i], "=") && i+1 < len(flags) {
+				i++
+				build = append(build, flags[i])
+			}
+		case "args":
+			return build
+		}
+	}
+	return build
+}
+
+// hasGoTestFlag returns true if the 'go test' flag is specified before "-args",
+// e.g. "run" for "-run=TestX", "-run TestX" or "-test.run=TestX".
+func hasGoTestFlag(args []string, name string) bool {
+	flags, _ := splitPackageArgs(args)
+	for _, f := range flags {
+		if f == "-args" || f == "--args" {
+			return false
+		}
+		n := strings.SplitN(strings.TrimLeft(f, "-"), "=", 2)[0]
+		if strings.HasPrefix(f, "-") && (n == name || n == "test."+name) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,93 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func Test_splitPackageArgs(t *testing.T) {
+	tests := []struct {
+		name      string
+		args      []string
+		wantFlags []string
+		wantPkgs  []string
+	}{
+		{
+			name:      "Separate packages from flags with values",
+			args:      []string{"-cover", "-run", "TestX", "./...", "-count=1", "-tags", "integration", "./cmd"},
+			wantFlags: []string{"-cover", "-run", "TestX", "-count=1", "-tags", "integration"},
+			wantPkgs:  []string{"./...", "./cmd"},
+		},
+		{
+			name:      "Separate packages from the test flags with the test. prefix",
+			args:      []string{"-test.run", "TestX", "./...", "-test.count", "3", "-test.timeout", "1m"},
+			wantFlags: []string{"-test.run", "TestX", "-test.count", "3", "-test.timeout", "1m"},
+			wantPkgs:  []string{"./..."},
+		},
+		{
+			name:      "Separate packages from the compiler flag",
+			args:      []string{"-compiler", "gccgo", "./cmd"},
+			wantFlags: []string{"-compiler", "gccgo"},
+			wantPkgs:  []string{"./cmd"},
+		},
+		{
+			name:      "Regard the arguments after -args as flags",
+			args:      []string{"./...", "-args", "-update", "golden"},
+			wantFlags: []string{"-args", "-update", "golden"},
+			wantPkgs:  []string{"./..."},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			gotFlags, gotPkgs := splitPackageArgs(tt.args)
+			if diff := cmp.Diff(tt.wantFlags, gotFlags); diff != "" {
+				t.Errorf("flags mismatch (-want +got):\n%s", diff)
+			}
+			if diff := cmp.Diff(tt.wantPkgs, gotPkgs); diff != "" {
+				t.Errorf("packages mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func Test_hasGoTestFlag(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+		want bool
+	}{
+		{name: "Find the flag with the value in the next argument", args: []string{"-run", "TestX", "./..."}, want: true},
+		{name: "Find the flag with '='", args: []string{"./...", "-test.run=TestX"}, want: true},
+		{name: "Ignore the value of another flag", args: []string{"-tags", "run", "./..."}, want: false},
+		{name: "Ignore the flags after -args", args: []string{"./...", "-args", "-run", "TestX"}, want: false},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			if got := hasGoTestFlag(tt.args, "run"); got != tt.want {
+				t.Errorf("hasGoTestFlag() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_decodeGoPackages(t *testing.T) {
+	t.Run("Decode the stream of JSON objects", func(t *testing.T) {
+		out := `{"ImportPath": "example/a", "Dir": "/src/a", "TestGoFiles": ["a_test.go"]}
+{"ImportPath": "example/b", "Dir": "/src/b", "Deps": ["example/a"]}`
+		got, err := decodeGoPackages(strings.NewReader(out))
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := []goPackage{
+			{ImportPath: "example/a", Dir: "/src/a", TestGoFiles: []string{"a_test.go"}},
+			{ImportPath: "example/b", Dir: "/src/b", Deps: []string{"example/a"}},
+		}
+		if diff := cmp.Diff(want, got); diff != "" {
+			t.Errorf("decodeGoPackages() mismatch (-want +got):\n%s", diff)
+		}
+	})
+}
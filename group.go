@@ -0,0 +1,157 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+)
+
+// testGroup is a group of the packages that is tested by a 'go test' command.
+type testGroup struct {
+	// Name is the label of the group that is shown in the results.
+	// It is empty if the packages are split automatically.
+	Name string `json:"name"`
+	// Packages is the package patterns of the group, e.g. ["./integration/..."].
+	Packages []string `json:"packages"`
+	// Flags is the 'go test' flags added to the arguments, e.g. ["-tags=integration"].
+	Flags []string `json:"flags"`
+	// Env is the environment variables, e.g. {"DB_HOST": "localhost"}.
+	Env map[string]string `json:"env"`
+}
+
+// groupFile is the structure of the group configuration file.
+type groupFile struct {
+	Groups []testGroup `json:"groups"`
+}
+
+// loadTestGroups loads the groups of the packages from the JSON file.
+func loadTestGroups(path string) ([]testGroup, error) {
+	b, err := os.ReadFile(filepath.Clean(path))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	var f groupFile
+	if err := json.Unmarshal(b, &f); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	if len(f.Groups) == 0 {
+		return nil, fmt.Errorf("%w: %s has no groups", errInvalidOption, path)
+	}
+
+	names := map[string]bool{}
+	for _, g := range f.Groups {
+		if g.Name == "" {
+			return nil, fmt.Errorf("%w: group without name in %s", errInvalidOption, path)
+		}
+		if names[g.Name] {
+			return nil, fmt.Errorf("%w: duplicate group name '%s' in %s", errInvalidOption, g.Name, path)
+		}
+		if len(g.Packages) == 0 {
+			return nil, fmt.Errorf("%w: group '%s' has no packages in %s", errInvalidOption, g.Name, path)
+		}
+		names[g.Name] = true
+	}
+	return f.Groups, nil
+}
+
+// args returns the 'go test' arguments of the group. The package arguments
+// are replaced with the packages of the group.
+func (g testGroup) args(args []string) []string {
+	flags, _ := splitPackageArgs(args)
+	return joinPackageArgs(append(append([]string{}, g.Flags...), flags...), g.Packages)
+}
+
+// splitGroups splits the packages that have tests into n groups.
+// The groups are balanced by the number of the packages.
+func splitGroups(args []string, n int) ([]testGroup, error) {
+	flags, patterns := splitPackageArgs(args)
+	pkgs, err := goListPackages(flags, patterns)
+	if err != nil {
+		return nil, err
+	}
+	units := []shardUnit{}
+	for _, p := range pkgs {
+		if p.hasTests() {
+			units = append(units, shardUnit{pkg: p.ImportPath, duration: 1})
+		}
+	}
+
+	groups := []testGroup{}
+	for _, s := range assignShards(units, n) {
+		if len(s) == 0 {
+			continue
+		}
+		g := testGroup{Packages: make([]string, 0, len(s))}
+		for _, u := range s {
+			g.Packages = append(g.Packages, u.pkg)
+		}
+		sort.Strings(g.Packages)
+		groups = append(groups, g)
+	}
+	return groups, nil
+}
+
+// testGroups returns the groups in the file of -hottest.groups, or the packages split into
+// the number of -hottest.parallel groups.
+func (h *hottest) testGroups() ([]testGroup, error) {
+	if h.opts.groups != "" {
+		return loadTestGroups(h.opts.groups)
+	}
+	return splitGroups(h.args, h.opts.parallel)
+}
+
+// runGroups runs a 'go test' command for each group concurrently. At most parallel
+// commands run at the same time. Each command is parsed by its own child hottest,
+// so the messages of the commands are never interleaved. After all commands finish,
+// the results of the children are added to h in the order of the groups.
+// Even if a command fails, the remaining commands are run. It returns the first error in the order of the groups.
+func (h *hottest) runGroups(groups []testGroup, parallel int) error {
+	h.interval.Start()
+	defer h.interval.End()
+
+	if h.log != nil {
+		h.log = &lockedWriter{w: h.log}
+	}
+
+	children := make([]*hottest, len(groups))
+	errs := make([]error, len(groups))
+	sem := make(chan struct{}, parallel)
+	var wg sync.WaitGroup
+	for i, g := range groups {
+		children[i] = h.newChild(environ(g.Env))
+		wg.Add(1)
+		go func(i int, g testGroup) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+			errs[i] = children[i].runTest(g.args(h.args))
+		}(i, g)
+	}
+	wg.Wait()
+
+	var firstErr error
+	for i, child := range children {
+		h.Merge(groups[i].Name, child.Aggregator)
+		if errs[i] != nil && firstErr == nil {
+			firstErr = errs[i]
+		}
+	}
+	return firstErr
+}
+
+// lockedWriter is an io.Writer that is safe for concurrent use.
+type lockedWriter struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+// Write writes p to the underlying writer while holding the lock.
+func (l *lockedWriter) Write(p []byte) (int, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.w.Write(p)
+}
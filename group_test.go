@@ -0,0 +1,81 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func Test_loadTestGroups(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    []testGroup
+		wantErr bool
+	}{
+		{
+			name: "Load groups",
+			content: `{"groups": [
+				{"name": "unit", "packages": ["./internal/..."]},
+				{"name": "integration", "packages": ["./integration/..."], "flags": ["-tags=integration"], "env": {"DB_HOST": "localhost"}}
+			]}`,
+			want: []testGroup{
+				{Name: "unit", Packages: []string{"./internal/..."}},
+				{Name: "integration", Packages: []string{"./integration/..."}, Flags: []string{"-tags=integration"}, Env: map[string]string{"DB_HOST": "localhost"}},
+			},
+		},
+		{name: "No groups", content: `{"groups": []}`, wantErr: true},
+		{name: "Group without name", content: `{"groups": [{"packages": ["./..."]}]}`, wantErr: true},
+		{name: "Group without packages", content: `{"groups": [{"name": "unit"}]}`, wantErr: true},
+		{name: "Duplicate names", content: `{"groups": [{"name": "a", "packages": ["./a"]}, {"name": "a", "packages": ["./b"]}]}`, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "groups.json")
+			if err := os.WriteFile(path, []byte(tt.content), 0o600); err != nil {
+				t.Fatal(err)
+			}
+			got, err := loadTestGroups(path)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("loadTestGroups() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if diff := cmp.Diff(tt.want, got); !tt.wantErr && diff != "" {
+				t.Errorf("loadTestGroups() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func Test_testGroup_args(t *testing.T) {
+	g := testGroup{Packages: []string{"./integration/..."}, Flags: []string{"-tags=integration"}}
+	got := g.args([]string{"-cover", "./...", "-args", "-update"})
+	want := []string{"-tags=integration", "-cover", "./integration/...", "-args", "-update"}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("args() mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func Test_lockedWriter(t *testing.T) {
+	var b bytes.Buffer
+	w := &lockedWriter{w: &b}
+	done := make(chan struct{})
+	for i := 0; i < 10; i++ {
+		go func() {
+			defer func() { done <- struct{}{} }()
+			for j := 0; j < 100; j++ {
+				if _, err := w.Write([]byte("line\n")); err != nil {
+					t.Error(err)
+				}
+			}
+		}()
+	}
+	for i := 0; i < 10; i++ {
+		<-done
+	}
+	if got, want := b.Len(), 10*100*len("line\n"); got != want {
+		t.Errorf("written %d bytes, want %d", got, want)
+	}
+}
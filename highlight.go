@@ -0,0 +1,152 @@
+package main
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/fatih/color"
+)
+
+// The colors of the error messages. The expected values and the removed lines of a diff are red,
+// and the actual values and the added lines are green, as the diff of a failed example.
+var (
+	// messageColor colors the error message that is not a part of a recognized assertion format.
+	messageColor = color.RedString
+	// wantColor colors the expected value and the removed line.
+	wantColor = color.New(color.FgRed, color.Bold).SprintFunc()
+	// gotColor colors the actual value and the added line.
+	gotColor = color.New(color.FgGreen, color.Bold).SprintFunc()
+)
+
+var (
+	// cmpDiffHeaderRegexp matches the header of a cmp.Diff block, e.g. "mismatch (-want +got):".
+	cmpDiffHeaderRegexp = regexp.MustCompile(`\(-(\w+) \+(\w+)\):?\s*$`)
+	// gotWantRegexp matches the "got X, want Y" pattern, e.g. "a_test.go:10: got 1, want 2".
+	gotWantRegexp = regexp.MustCompile(`^(.*?\bgot:?\s+)(.+?)(,?\s+want:?\s+)(.+?)$`)
+	// wantGotRegexp matches the "want Y, got X" pattern, e.g. "a_test.go:10: want 2, got 1".
+	wantGotRegexp = regexp.MustCompile(`^(.*?\bwant:?\s+)(.+?)(,?\s+got:?\s+)(.+?)$`)
+)
+
+// highlightMode is the assertion format of the block that the highlighter is in.
+type highlightMode int
+
+const (
+	// highlightNone is not in a block.
+	highlightNone highlightMode = iota
+	// highlightCmpDiff is in the lines of cmp.Diff after "(-want +got)".
+	highlightCmpDiff
+	// highlightTestify is in the labeled output of testify ("Error Trace:", "Error:", etc.).
+	highlightTestify
+)
+
+// messageHighlighter colors the error messages by recognizing the assertion formats:
+// cmp.Diff blocks, testify's labeled output and the "got X, want Y" pattern.
+// It is stateful because a block continues over the lines.
+type messageHighlighter struct {
+	mode highlightMode
+	// indent is the indent of the line that starts the block. The block ends at the line
+	// that is not indented more than it.
+	indent int
+	// minusIsGot is true if the removed lines of the cmp.Diff block are the actual value, e.g. "(-got +want)".
+	minusIsGot bool
+	// label is the current label of testify's labeled output, e.g. "Error Trace".
+	label string
+}
+
+// highlight returns the colored message. It returns false if the line should be omitted,
+// e.g. the trace lines of testify except for the first one.
+func (m *messageHighlighter) highlight(msg string) (string, bool) {
+	body := strings.TrimLeft(msg, " ")
+	indent := len(msg) - len(body)
+	if m.mode != highlightNone && indent <= m.indent {
+		m.mode = highlightNone
+	}
+
+	switch m.mode {
+	case highlightCmpDiff:
+		return m.highlightCmpDiff(msg, body), true
+	case highlightTestify:
+		return m.highlightTestify(msg, body)
+	}
+
+	if sub := cmpDiffHeaderRegexp.FindStringSubmatch(msg); sub != nil {
+		m.mode, m.indent = highlightCmpDiff, indent
+		m.minusIsGot = sub[1] == "got" || sub[1] == "actual"
+		return messageColor(msg), true
+	}
+	if strings.HasPrefix(strings.TrimSpace(body), "Error Trace:") {
+		// The first line of testify's output is "a_test.go:10: " and the labeled lines follow it.
+		m.mode, m.indent = highlightTestify, indent-1
+		return m.highlightTestify(msg, body)
+	}
+	return highlightGotWant(msg), true
+}
+
+// highlightCmpDiff colors the removed and the added lines of a cmp.Diff block.
+// cmp.Diff may indent the lines with non-breaking spaces, so only the first character after the spaces is checked.
+func (m *messageHighlighter) highlightCmpDiff(msg, body string) string {
+	switch {
+	case strings.HasPrefix(body, "-") && m.minusIsGot, strings.HasPrefix(body, "+") && !m.minusIsGot:
+		return gotColor(msg)
+	case strings.HasPrefix(body, "-"), strings.HasPrefix(body, "+"):
+		return wantColor(msg)
+	default:
+		return msg
+	}
+}
+
+// highlightTestify colors testify's labeled output, e.g. "\tError:      \tNot equal:".
+// The trace lines except for the first one and the "Test:" line, which is the same as the "--- FAIL" line, are omitted.
+func (m *messageHighlighter) highlightTestify(msg, body string) (string, bool) {
+	fields := strings.SplitN(strings.TrimPrefix(body, "\t"), "\t", 2)
+	label := strings.TrimSpace(fields[0])
+	value := ""
+	if len(fields) == 2 {
+		value = strings.TrimSpace(fields[1])
+	}
+	if label != "" {
+		m.label = strings.TrimSuffix(label, ":")
+		if m.label == "Test" {
+			return "", false
+		}
+		return messageColor(msg), true
+	}
+
+	// The continuation line of the label.
+	switch m.label {
+	case "Error Trace", "Test":
+		return "", false
+	case "Diff":
+		switch {
+		case strings.HasPrefix(value, "-"):
+			return wantColor(msg), true
+		case strings.HasPrefix(value, "+"):
+			return gotColor(msg), true
+		}
+		return msg, true
+	case "Error":
+		switch {
+		case value == "Diff:":
+			// The diff of the expected and the actual values follows "Diff:" in the lines of "Error".
+			m.label = "Diff"
+			return msg, true
+		case strings.HasPrefix(value, "expected"):
+			return wantColor(msg), true
+		case strings.HasPrefix(value, "actual"):
+			return gotColor(msg), true
+		}
+	}
+	return messageColor(msg), true
+}
+
+// highlightGotWant colors the values of the "got X, want Y" pattern.
+// If the message does not match the pattern, the whole message is colored as an error message.
+func highlightGotWant(msg string) string {
+	if sub := gotWantRegexp.FindStringSubmatch(msg); sub != nil {
+		return messageColor(sub[1]) + gotColor(sub[2]) + messageColor(sub[3]) + wantColor(sub[4])
+	}
+	if sub := wantGotRegexp.FindStringSubmatch(msg); sub != nil {
+		return messageColor(sub[1]) + wantColor(sub[2]) + messageColor(sub[3]) + gotColor(sub[4])
+	}
+	return messageColor(msg)
+}
@@ -0,0 +1,86 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/fatih/color"
+	"github.com/google/go-cmp/cmp"
+)
+
+func Test_messageHighlighter(t *testing.T) {
+	noColor := color.NoColor
+	color.NoColor = false
+	t.Cleanup(func() {
+		color.NoColor = noColor
+	})
+
+	type line struct {
+		msg  string
+		want string
+	}
+	tests := []struct {
+		name  string
+		lines []line
+	}{
+		{
+			name: "Color the removed and the added lines of cmp.Diff",
+			lines: []line{
+				{"version_test.go:14: GetVersion() mismatch (-want +got):", messageColor("version_test.go:14: GetVersion() mismatch (-want +got):")},
+				{"      string(", "      string("},
+				{"    - \t\"(devel)\",", wantColor("    - \t\"(devel)\",")},
+				{"    + \t\"\",", gotColor("    + \t\"\",")},
+				{"      )", "      )"},
+				{"version_test.go:20: unexpected error", messageColor("version_test.go:20: unexpected error")},
+			},
+		},
+		{
+			name: "Color the removed lines as the actual value if the diff is (-got +want)",
+			lines: []line{
+				{"a_test.go:10: (-got +want)", messageColor("a_test.go:10: (-got +want)")},
+				{"    - 1", gotColor("    - 1")},
+				{"    + 2", wantColor("    + 2")},
+			},
+		},
+		{
+			name: "Color the expected and the actual values of testify and omit the trace lines",
+			lines: []line{
+				{"a_test.go:12: ", messageColor("a_test.go:12: ")},
+				{"    \tError Trace:\t/src/a_test.go:12", messageColor("    \tError Trace:\t/src/a_test.go:12")},
+				{"    \t            \t/src/helper_test.go:30", ""},
+				{"    \tError:      \tNot equal: ", messageColor("    \tError:      \tNot equal: ")},
+				{"    \t            \texpected: 1", wantColor("    \t            \texpected: 1")},
+				{"    \t            \tactual  : 2", gotColor("    \t            \tactual  : 2")},
+				{"    \t            \t", messageColor("    \t            \t")},
+				{"    \t            \tDiff:", "    \t            \tDiff:"},
+				{"    \t            \t--- Expected", wantColor("    \t            \t--- Expected")},
+				{"    \t            \t@@ -1 +1 @@", "    \t            \t@@ -1 +1 @@"},
+				{"    \t            \t+2", gotColor("    \t            \t+2")},
+				{"    \tTest:       \tTestA", ""},
+			},
+		},
+		{
+			name: "Color the values of the got/want pattern",
+			lines: []line{
+				{"a_test.go:10: got 1, want 2", messageColor("a_test.go:10: got ") + gotColor("1") + messageColor(", want ") + wantColor("2")},
+				{"a_test.go:11: want: [a], got: [b]", messageColor("a_test.go:11: want: ") + wantColor("[a]") + messageColor(", got: ") + gotColor("[b]")},
+				{"a_test.go:12: I forgot to want nothing", messageColor("a_test.go:12: I forgot to want nothing")},
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			h := &messageHighlighter{}
+			for _, l := range tt.lines {
+				got, ok := h.highlight(l.msg)
+				if ok != (l.want != "") {
+					t.Errorf("highlight(%q) ok = %v, want %v", l.msg, ok, l.want != "")
+					continue
+				}
+				if diff := cmp.Diff(l.want, got); diff != "" {
+					t.Errorf("highlight(%q) mismatch (-want +got):\n%s", l.msg, diff)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,346 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/nao1215/hottest/testjson"
+)
+
+const (
+	// defaultHistoryDir is the default directory where the test results are saved.
+	defaultHistoryDir = ".hottest/history"
+	// historyFileTimeFormat is the time format of the history file name.
+	// The history files are sorted in chronological order by their names.
+	historyFileTimeFormat = "20060102T150405.000000000Z"
+	// defaultHistoryWindow is the default number of runs to find flaky tests.
+	defaultHistoryWindow = 10
+	// defaultRegressionThreshold is the default ratio of the elapsed time to the average
+	// of the previous runs that is regarded as a duration regression.
+	defaultRegressionThreshold = 1.5
+	// minRegressionDiff is the minimum difference of the elapsed time that is regarded as
+	// a duration regression. It prevents the tests that finish in a moment from being reported.
+	minRegressionDiff = 0.1
+	// flakyFlips is the minimum number of changes between pass and fail to regard the test as flaky.
+	flakyFlips = 2
+)
+
+// errNoHistory is an error that occurs when there is not enough history to compare.
+var errNoHistory = errors.New("not enough history to compare: run 'hottest -hottest.history ./...' at least twice")
+
+// historyRun is the test results of a run that are saved in the history directory.
+type historyRun struct {
+	// Started is the time when the run started.
+	Started time.Time `json:"started"`
+	// Commit is the git commit hash of the run. It is empty if the git command is not available.
+	Commit string `json:"commit"`
+	// Branch is the git branch of the run. It is empty if the git command is not available.
+	Branch string `json:"branch"`
+	// Tests is the results of the tests.
+	Tests []historyTest `json:"tests"`
+}
+
+// historyTest is the result of a test that is saved in the history directory.
+type historyTest struct {
+	// Source is the label of the configuration that the test ran under, e.g. the name of the
+	// -hottest.matrix configuration. It is empty unless the results of multiple runs are merged.
+	Source  string  `json:"source,omitempty"`
+	Package string  `json:"package"`
+	Name    string  `json:"name"`
+	Action  string  `json:"action"`
+	Elapsed float64 `json:"elapsed"`
+}
+
+// key returns the key that identifies the test across runs.
+// The same test under different configurations has different keys.
+func (t historyTest) key() string {
+	return t.Source + " " + t.Package + " " + t.Name
+}
+
+// label returns the label of the source, e.g. "[linux] ". It returns an empty string if the source is empty.
+func (t historyTest) label() string {
+	if t.Source == "" {
+		return ""
+	}
+	return "[" + t.Source + "] "
+}
+
+// newHistoryRun returns the test results of the run to be saved.
+func (h *hottest) newHistoryRun() historyRun {
+	run := historyRun{
+		Started: h.interval.Started.UTC(),
+		Commit:  gitOutput("rev-parse", "HEAD"),
+		Branch:  gitOutput("rev-parse", "--abbrev-ref", "HEAD"),
+		Tests:   historyTests(h.Results),
+	}
+	return run
+}
+
+// historyTests returns the results of the finished tests.
+func historyTests(r *testjson.Results) []historyTest {
+	tests := []historyTest{}
+	for _, t := range r.Tests("") {
+		if t.Action == "" {
+			continue
+		}
+		tests = append(tests, historyTest{
+			Source:  t.Source,
+			Package: t.Package,
+			Name:    t.Name,
+			Action:  t.Action,
+			Elapsed: t.Elapsed,
+		})
+	}
+	return tests
+}
+
+// saveHistory saves the test results of the run to the history directory.
+func (h *hottest) saveHistory(dir string) error {
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		return fmt.Errorf("failed to create history directory: %w", err)
+	}
+
+	run := h.newHistoryRun()
+	path := filepath.Join(dir, run.Started.Format(historyFileTimeFormat)+".json")
+	b, err := json.MarshalIndent(run, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(path, b, 0o600); err != nil {
+		return fmt.Errorf("failed to save history: %w", err)
+	}
+	return nil
+}
+
+// loadHistory loads the last n runs from the history directory in chronological order.
+// If n is 0 or less, all runs are loaded.
+func loadHistory(dir string, n int) ([]historyRun, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(paths)
+	if n > 0 && len(paths) > n {
+		paths = paths[len(paths)-n:]
+	}
+
+	runs := make([]historyRun, 0, len(paths))
+	for _, path := range paths {
+		b, err := os.ReadFile(filepath.Clean(path))
+		if err != nil {
+			return nil, err
+		}
+		var run historyRun
+		if err := json.Unmarshal(b, &run); err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+		}
+		runs = append(runs, run)
+	}
+	return runs, nil
+}
+
+// gitOutput returns the output of the git command. It returns an empty string if the command fails.
+func gitOutput(args ...string) string {
+	out, err := exec.Command("git", args...).Output() //#nosec
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(out))
+}
+
+// durationRegression is a test whose elapsed time is longer than the previous runs.
+type durationRegression struct {
+	test     historyTest
+	previous float64
+}
+
+// flakyTest is a test whose result changes between pass and fail.
+type flakyTest struct {
+	test     historyTest
+	failures int
+	runs     int
+}
+
+// historyComparison is the result of comparing the latest run with the previous runs.
+type historyComparison struct {
+	latest      historyRun
+	previous    historyRun
+	newlyFailed []historyTest
+	newlyFixed  []historyTest
+	flaky       []flakyTest
+	regressions []durationRegression
+}
+
+// compareHistory compares the latest run with the previous runs.
+// runs must be in chronological order and contain at least two runs.
+func compareHistory(runs []historyRun, threshold float64) historyComparison {
+	latest := runs[len(runs)-1]
+	previous := runs[len(runs)-2]
+	c := historyComparison{latest: latest, previous: previous}
+
+	prevActions := map[string]string{}
+	for _, t := range previous.Tests {
+		prevActions[t.key()] = t.Action
+	}
+
+	// history holds the results of each test in chronological order.
+	history := map[string][]historyTest{}
+	order := []string{}
+	for _, run := range runs {
+		for _, t := range run.Tests {
+			if _, ok := history[t.key()]; !ok {
+				order = append(order, t.key())
+			}
+			history[t.key()] = append(history[t.key()], t)
+		}
+	}
+
+	for _, t := range latest.Tests {
+		switch {
+		case t.Action == testjson.ActionFail && prevActions[t.key()] == testjson.ActionPass:
+			c.newlyFailed = append(c.newlyFailed, t)
+		case t.Action == testjson.ActionPass && prevActions[t.key()] == testjson.ActionFail:
+			c.newlyFixed = append(c.newlyFixed, t)
+		}
+
+		if t.Action != testjson.ActionPass {
+			continue
+		}
+		if avg, ok := averageElapsed(history[t.key()][:len(history[t.key()])-1]); ok &&
+			t.Elapsed > avg*threshold && t.Elapsed-avg >= minRegressionDiff {
+			c.regressions = append(c.regressions, durationRegression{test: t, previous: avg})
+		}
+	}
+
+	for _, key := range order {
+		tests := history[key]
+		flips, failures, last := 0, 0, ""
+		for _, t := range tests {
+			if t.Action == testjson.ActionFail {
+				failures++
+			}
+			if t.Action != testjson.ActionPass && t.Action != testjson.ActionFail {
+				continue
+			}
+			if last != "" && last != t.Action {
+				flips++
+			}
+			last = t.Action
+		}
+		if flips >= flakyFlips {
+			c.flaky = append(c.flaky, flakyTest{test: tests[len(tests)-1], failures: failures, runs: len(tests)})
+		}
+	}
+	return c
+}
+
+// averageElapsed returns the average elapsed time of the passed tests.
+// It returns false if there are no passed tests.
+func averageElapsed(tests []historyTest) (float64, bool) {
+	sum, n := 0.0, 0
+	for _, t := range tests {
+		if t.Action == testjson.ActionPass {
+			sum += t.Elapsed
+			n++
+		}
+	}
+	if n == 0 {
+		return 0, false
+	}
+	return sum / float64(n), true
+}
+
+// print prints the comparison result.
+func (c historyComparison) print(w io.Writer, window int) {
+	fmt.Fprintf(w, "Compared %s with %s\n", describeRun(c.latest), describeRun(c.previous))
+
+	fmt.Fprintf(w, "[Newly failing tests]\n")
+	if len(c.newlyFailed) == 0 {
+		fmt.Fprintf(w, " none\n")
+	}
+	for _, t := range c.newlyFailed {
+		fmt.Fprintf(w, " %s%s %s\n", t.label(), color.RedString(t.Name), t.Package)
+	}
+
+	fmt.Fprintf(w, "[Newly fixed tests]\n")
+	if len(c.newlyFixed) == 0 {
+		fmt.Fprintf(w, " none\n")
+	}
+	for _, t := range c.newlyFixed {
+		fmt.Fprintf(w, " %s%s %s\n", t.label(), color.GreenString(t.Name), t.Package)
+	}
+
+	fmt.Fprintf(w, "[Flaky tests] (last %d runs)\n", window)
+	if len(c.flaky) == 0 {
+		fmt.Fprintf(w, " none\n")
+	}
+	for _, f := range c.flaky {
+		fmt.Fprintf(w, " %s%s %s (%d failures in %d runs)\n", f.test.label(), color.YellowString(f.test.Name), f.test.Package, f.failures, f.runs)
+	}
+
+	fmt.Fprintf(w, "[Duration regressions]\n")
+	if len(c.regressions) == 0 {
+		fmt.Fprintf(w, " none\n")
+	}
+	for _, r := range c.regressions {
+		fmt.Fprintf(w, " %s%s %s %s -> %s%s\n", r.test.label(), color.YellowString(r.test.Name), r.test.Package,
+			elapsedString(r.previous), elapsedString(r.test.Elapsed), changeRate(r.previous, r.test.Elapsed))
+	}
+}
+
+// changeRate returns the rate of change from before to after, e.g. " (+50%)".
+// It returns an empty string if before is zero.
+func changeRate(before, after float64) string {
+	if before == 0 {
+		return ""
+	}
+	return fmt.Sprintf(" (%+.0f%%)", (after/before-1)*100)
+}
+
+// describeRun returns the description of the run, e.g. "2023-01-01T00:00:00Z (main@0123abc)".
+func describeRun(run historyRun) string {
+	commit := run.Commit
+	if len(commit) > 7 {
+		commit = commit[:7]
+	}
+	if commit == "" {
+		return run.Started.Format(time.RFC3339)
+	}
+	return fmt.Sprintf("%s (%s@%s)", run.Started.Format(time.RFC3339), run.Branch, commit)
+}
+
+// runHistory runs the 'hottest history' subcommand.
+func runHistory(args []string) error {
+	fs := flag.NewFlagSet("history", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	dir := fs.String("dir", defaultHistoryDir, "history directory")
+	window := fs.Int("n", defaultHistoryWindow, "number of runs to find flaky tests and duration regressions")
+	threshold := fs.Float64("threshold", defaultRegressionThreshold,
+		"ratio of the elapsed time to the average of the previous runs regarded as a duration regression")
+	if err := fs.Parse(args); err != nil {
+		return fmt.Errorf("%w: %s", errInvalidOption, err.Error())
+	}
+	if *window < 2 {
+		return fmt.Errorf("%w: -n must be 2 or more", errInvalidOption)
+	}
+
+	runs, err := loadHistory(*dir, *window)
+	if err != nil {
+		return err
+	}
+	if len(runs) < 2 {
+		return errNoHistory
+	}
+	compareHistory(runs, *threshold).print(os.Stdout, *window)
+	return nil
+}
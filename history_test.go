@@ -0,0 +1,135 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/nao1215/hottest/testjson"
+)
+
+func Test_saveHistory(t *testing.T) {
+	t.Run("Save the test results and load them in chronological order", func(t *testing.T) {
+		dir := t.TempDir()
+		h := newHottestFromLog(t, "testdata/sample.json")
+		for _, started := range []time.Time{
+			time.Date(2023, 1, 2, 0, 0, 0, 0, time.UTC),
+			time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC),
+			time.Date(2023, 1, 3, 0, 0, 0, 0, time.UTC),
+		} {
+			h.interval.Started = started
+			if err := h.saveHistory(dir); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		runs, err := loadHistory(dir, 2)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got := []int{}
+		for _, run := range runs {
+			got = append(got, run.Started.Day())
+		}
+		if diff := cmp.Diff([]int{2, 3}, got); diff != "" {
+			t.Errorf("loadHistory() mismatch (-want +got):\n%s", diff)
+		}
+		if len(runs[0].Tests) != 6 {
+			t.Errorf("got %d tests, want 6", len(runs[0].Tests))
+		}
+	})
+}
+
+func Test_compareHistory(t *testing.T) {
+	run := func(tests ...historyTest) historyRun {
+		return historyRun{Tests: tests}
+	}
+	test := func(name, action string, elapsed float64) historyTest {
+		return historyTest{Package: "p", Name: name, Action: action, Elapsed: elapsed}
+	}
+	runs := []historyRun{
+		run(test("TestFlaky", testjson.ActionPass, 0), test("TestBroken", testjson.ActionPass, 0), test("TestFixed", testjson.ActionFail, 0), test("TestSlow", testjson.ActionPass, 0.2)),
+		run(test("TestFlaky", testjson.ActionFail, 0), test("TestBroken", testjson.ActionPass, 0), test("TestFixed", testjson.ActionFail, 0), test("TestSlow", testjson.ActionPass, 0.2)),
+		run(test("TestFlaky", testjson.ActionPass, 0), test("TestBroken", testjson.ActionFail, 0), test("TestFixed", testjson.ActionPass, 0), test("TestSlow", testjson.ActionPass, 1)),
+	}
+
+	c := compareHistory(runs, defaultRegressionThreshold)
+
+	names := func(tests []historyTest) []string {
+		got := []string{}
+		for _, t := range tests {
+			got = append(got, t.Name)
+		}
+		return got
+	}
+	t.Run("Find newly failing tests", func(t *testing.T) {
+		if diff := cmp.Diff([]string{"TestBroken"}, names(c.newlyFailed)); diff != "" {
+			t.Errorf("newly failing tests mismatch (-want +got):\n%s", diff)
+		}
+	})
+	t.Run("Find newly fixed tests", func(t *testing.T) {
+		if diff := cmp.Diff([]string{"TestFlaky", "TestFixed"}, names(c.newlyFixed)); diff != "" {
+			t.Errorf("newly fixed tests mismatch (-want +got):\n%s", diff)
+		}
+	})
+	t.Run("Find flaky tests", func(t *testing.T) {
+		if len(c.flaky) != 1 || c.flaky[0].test.Name != "TestFlaky" || c.flaky[0].failures != 1 {
+			t.Errorf("flaky tests = %+v, want TestFlaky with 1 failure", c.flaky)
+		}
+	})
+	t.Run("Find duration regressions", func(t *testing.T) {
+		if len(c.regressions) != 1 || c.regressions[0].test.Name != "TestSlow" || c.regressions[0].previous != 0.2 {
+			t.Errorf("regressions = %+v, want TestSlow", c.regressions)
+		}
+
+		var b bytes.Buffer
+		c.print(&b, defaultHistoryWindow)
+		if want := "TestSlow p 200ms -> 1s (+400%)"; !strings.Contains(b.String(), want) {
+			t.Errorf("output does not contain %q\n%s", want, b.String())
+		}
+	})
+}
+
+func Test_historyOfMatrixRun(t *testing.T) {
+	h := newHottestForLog()
+	h.Merge("linux", newHottestFromLog(t, "testdata/sample.json").Aggregator)
+	h.Merge("windows", newHottestFromLog(t, "testdata/sample.json").Aggregator)
+
+	t.Run("Save the tests of each configuration separately", func(t *testing.T) {
+		tests := historyTests(h.Results)
+		if len(tests) != 12 {
+			t.Fatalf("got %d tests, want 12", len(tests))
+		}
+		keys := map[string]bool{}
+		for _, test := range tests {
+			keys[test.key()] = true
+		}
+		if len(keys) != 12 {
+			t.Errorf("got %d keys, want 12", len(keys))
+		}
+		if tests[0].Source != "linux" || tests[6].Source != "windows" {
+			t.Errorf("sources = %q and %q, want linux and windows", tests[0].Source, tests[6].Source)
+		}
+	})
+
+	t.Run("Do not regard different results under different configurations as flaky", func(t *testing.T) {
+		test := func(source, action string) historyTest {
+			return historyTest{Source: source, Package: "p", Name: "TestX", Action: action}
+		}
+		run := historyRun{Tests: []historyTest{test("linux", testjson.ActionPass), test("windows", testjson.ActionFail)}}
+		c := compareHistory([]historyRun{run, run, run}, defaultRegressionThreshold)
+		if len(c.flaky) != 0 || len(c.newlyFailed) != 0 || len(c.newlyFixed) != 0 {
+			t.Errorf("flaky = %+v, newly failed = %+v, newly fixed = %+v, want none", c.flaky, c.newlyFailed, c.newlyFixed)
+		}
+
+		fixed := historyRun{Tests: []historyTest{test("linux", testjson.ActionPass), test("windows", testjson.ActionPass)}}
+		c = compareHistory([]historyRun{run, fixed}, defaultRegressionThreshold)
+		var b bytes.Buffer
+		c.print(&b, defaultHistoryWindow)
+		if want := "[windows] TestX p"; !strings.Contains(b.String(), want) {
+			t.Errorf("output does not contain %q\n%s", want, b.String())
+		}
+	})
+}
@@ -0,0 +1,309 @@
+package main
+
+import (
+	"fmt"
+	"go/scanner"
+	"go/token"
+	"html"
+	"html/template"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/nao1215/hottest/testjson"
+	"github.com/nao1215/hottest/version"
+)
+
+// sourceContextLines is the number of lines shown before and after the line where the test failed.
+const sourceContextLines = 3
+
+// htmlReport is the data of the HTML report.
+type htmlReport struct {
+	Version  string
+	Started  string
+	Finished string
+	Duration string
+	Stats    testjson.TestStats
+	Packages []htmlPackage
+}
+
+// htmlPackage is a package in the HTML report.
+type htmlPackage struct {
+	Name    string
+	Action  string
+	Elapsed string
+	Stats   testjson.TestStats
+	Tests   []htmlTest
+}
+
+// htmlTest is a test in the HTML report.
+type htmlTest struct {
+	Name     string
+	Depth    int
+	Action   string
+	Elapsed  string
+	Messages []string
+	Source   *htmlSource
+}
+
+// htmlSource is the source code around the line where the test failed.
+type htmlSource struct {
+	File  string
+	Lines []htmlSourceLine
+}
+
+// htmlSourceLine is a line of the source code.
+type htmlSourceLine struct {
+	Number    int
+	Code      template.HTML
+	Highlight bool
+}
+
+// writeHTMLFile writes the HTML report to the file.
+func (h *hottest) writeHTMLFile(path string) error {
+	f, err := os.Create(filepath.Clean(path))
+	if err != nil {
+		return fmt.Errorf("failed to create %s: %w", path, err)
+	}
+	defer f.Close() //nolint
+
+	if err := writeHTML(f, h.newHTMLReport(packageDirs{})); err != nil {
+		return fmt.Errorf("failed to write HTML report: %w", err)
+	}
+	return f.Close()
+}
+
+// newHTMLReport returns the data of the HTML report.
+func (h *hottest) newHTMLReport(dirs packageDirs) htmlReport {
+	report := htmlReport{
+		Version:  version.GetVersion(),
+		Started:  h.interval.Started.Format(time.RFC3339),
+		Finished: h.interval.Finished.Format(time.RFC3339),
+		Duration: h.interval.Duration().String(),
+		Stats:    h.Stats,
+		Packages: make([]htmlPackage, 0, len(h.Results.Packages)),
+	}
+
+	for _, p := range h.Results.Packages {
+		pkg := htmlPackage{
+			Name:    p.Name,
+			Action:  p.Action,
+			Elapsed: elapsedString(p.Elapsed),
+			Stats:   p.Stats,
+			Tests:   make([]htmlTest, 0, len(p.Tests)),
+		}
+		for _, t := range p.Tests {
+			test := htmlTest{
+				Name:    t.Name,
+				Depth:   strings.Count(t.Name, "/"),
+				Action:  t.Action,
+				Elapsed: elapsedString(t.Elapsed),
+			}
+			switch t.Action {
+			case testjson.ActionFail:
+				test.Messages = t.Messages()
+				if loc, ok := findLocation(test.Messages); ok {
+					test.Source = readSourceContext(dirs.dir(p.Name), loc)
+				}
+			case testjson.ActionSkip:
+				test.Messages = t.Messages()
+			}
+			pkg.Tests = append(pkg.Tests, test)
+		}
+		report.Packages = append(report.Packages, pkg)
+	}
+	return report
+}
+
+// readSourceContext returns the highlighted source code around the location.
+// It returns nil if the source file cannot be read.
+func readSourceContext(dir string, loc Location) *htmlSource {
+	if dir == "" {
+		return nil
+	}
+	src, err := os.ReadFile(filepath.Clean(filepath.Join(dir, loc.File)))
+	if err != nil {
+		return nil
+	}
+
+	lines := highlightGo(src)
+	first := loc.Line - sourceContextLines
+	if first < 1 {
+		first = 1
+	}
+	last := loc.Line + sourceContextLines
+	if last > len(lines) {
+		last = len(lines)
+	}
+
+	source := &htmlSource{File: loc.File, Lines: []htmlSourceLine{}}
+	for n := first; n <= last; n++ {
+		source.Lines = append(source.Lines, htmlSourceLine{
+			Number:    n,
+			Code:      lines[n-1],
+			Highlight: n == loc.Line,
+		})
+	}
+	return source
+}
+
+// highlightGo returns the lines of the Go source code with syntax highlighting.
+// Keywords, literals and comments are wrapped in span elements.
+func highlightGo(src []byte) []template.HTML {
+	fset := token.NewFileSet()
+	file := fset.AddFile("", fset.Base(), len(src))
+	var s scanner.Scanner
+	s.Init(file, src, nil, scanner.ScanComments)
+
+	var b strings.Builder
+	offset := 0
+	for {
+		pos, tok, lit := s.Scan()
+		if tok == token.EOF {
+			break
+		}
+		class := tokenClass(tok)
+		if class == "" {
+			continue
+		}
+		start := file.Offset(pos)
+		end := start + len(lit)
+		if start < offset || end > len(src) {
+			continue
+		}
+		b.WriteString(html.EscapeString(string(src[offset:start])))
+		// The span elements must not cross lines because the source is split into lines.
+		for i, seg := range strings.Split(string(src[start:end]), "\n") {
+			if i > 0 {
+				b.WriteString("\n")
+			}
+			if seg != "" {
+				fmt.Fprintf(&b, `<span class="%s">%s</span>`, class, html.EscapeString(seg))
+			}
+		}
+		offset = end
+	}
+	b.WriteString(html.EscapeString(string(src[offset:])))
+
+	lines := []template.HTML{}
+	for _, l := range strings.Split(b.String(), "\n") {
+		lines = append(lines, template.HTML(l)) //#nosec G203 -- the source code is escaped above
+	}
+	return lines
+}
+
+// tokenClass returns the CSS class of the token. It returns an empty string
+// if the token is not highlighted.
+func tokenClass(tok token.Token) string {
+	switch {
+	case tok.IsKeyword():
+		return "kw"
+	case tok == token.STRING || tok == token.CHAR:
+		return "str"
+	case tok == token.INT || tok == token.FLOAT || tok == token.IMAG:
+		return "num"
+	case tok == token.COMMENT:
+		return "com"
+	}
+	return ""
+}
+
+// writeHTML writes the HTML report to w.
+func writeHTML(w io.Writer, report htmlReport) error {
+	tmpl, err := template.New("report").Funcs(template.FuncMap{
+		"indent": func(depth int) string { return fmt.Sprintf("%dem", depth*2) },
+	}).Parse(htmlTemplate)
+	if err != nil {
+		return err
+	}
+	return tmpl.Execute(w, report)
+}
+
+// htmlTemplate is the template of the HTML report. It does not depend on
+// external assets so that the report can be viewed offline.
+const htmlTemplate = `<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>HOTTEST report</title>
+<style>
+body { font-family: sans-serif; margin: 2em; color: #24292f; }
+table.summary { border-collapse: collapse; margin-bottom: 1em; }
+table.summary th, table.summary td { border: 1px solid #d0d7de; padding: 4px 12px; text-align: right; }
+.meta { color: #57606a; font-size: 0.9em; }
+.filter { margin: 1em 0; }
+details.pkg { border: 1px solid #d0d7de; border-radius: 6px; margin: 6px 0; padding: 4px 8px; }
+details.pkg > summary { cursor: pointer; font-weight: bold; }
+.test { padding: 2px 0; }
+.elapsed { color: #57606a; font-size: 0.9em; }
+.pass { color: #1a7f37; }
+.fail { color: #cf222e; }
+.skip { color: #0969da; }
+pre { background: #f6f8fa; padding: 8px; overflow-x: auto; margin: 4px 0; }
+.src .ln { color: #8c959f; user-select: none; display: inline-block; width: 4em; }
+.src .line { display: block; min-height: 1.2em; }
+.src .hl { background: #ffebe9; }
+.kw { color: #cf222e; }
+.str { color: #0a3069; }
+.num { color: #0550ae; }
+.com { color: #6e7781; font-style: italic; }
+</style>
+</head>
+<body>
+<h1>HOTTEST report</h1>
+<p class="meta">Started: {{.Started}} / Finished: {{.Finished}} / Duration: {{.Duration}} / hottest {{.Version}}</p>
+<table class="summary">
+<tr><th>PASS</th><th>FAIL</th><th>SKIP</th><th>TOTAL</th></tr>
+<tr><td class="pass">{{.Stats.Pass}}</td><td class="fail">{{.Stats.Fail}}</td><td class="skip">{{.Stats.Skip}}</td><td>{{.Stats.Total}}</td></tr>
+</table>
+<div class="filter">
+<input id="query" type="search" placeholder="Filter by package or test name">
+<label><input type="checkbox" class="status" value="pass" checked> pass</label>
+<label><input type="checkbox" class="status" value="fail" checked> fail</label>
+<label><input type="checkbox" class="status" value="skip" checked> skip</label>
+</div>
+{{range .Packages}}
+<details class="pkg" data-name="{{.Name}}"{{if eq .Action "fail"}} open{{end}}>
+<summary><span class="{{.Action}}">{{.Name}}</span> <span class="elapsed">({{.Stats.Pass}}/{{.Stats.Fail}}/{{.Stats.Skip}}, {{.Elapsed}})</span></summary>
+{{range .Tests}}
+<div class="test" data-name="{{.Name}}" data-action="{{.Action}}" style="margin-left: {{indent .Depth}}">
+<span class="{{.Action}}">{{if eq .Action "pass"}}&#10003;{{else if eq .Action "fail"}}&#10007;{{else if eq .Action "skip"}}&#8631;{{else}}?{{end}}</span>
+{{.Name}} <span class="elapsed">{{.Elapsed}}</span>
+{{if .Messages}}<pre>{{range .Messages}}{{.}}
+{{end}}</pre>{{end}}
+{{with .Source}}<pre class="src"><b>{{.File}}</b>
+{{range .Lines}}<span class="line{{if .Highlight}} hl{{end}}"><span class="ln">{{.Number}}</span>{{.Code}}</span>{{end}}</pre>{{end}}
+</div>
+{{end}}
+</details>
+{{end}}
+<script>
+(function () {
+  var query = document.getElementById("query");
+  var statuses = document.querySelectorAll("input.status");
+  function filter() {
+    var q = query.value.toLowerCase();
+    var enabled = {};
+    statuses.forEach(function (s) { enabled[s.value] = s.checked; });
+    document.querySelectorAll("details.pkg").forEach(function (pkg) {
+      var pkgMatch = pkg.dataset.name.toLowerCase().indexOf(q) !== -1;
+      var tests = pkg.querySelectorAll(".test");
+      var visible = 0;
+      tests.forEach(function (t) {
+        var show = enabled[t.dataset.action] !== false &&
+          (pkgMatch || t.dataset.name.toLowerCase().indexOf(q) !== -1);
+        t.style.display = show ? "" : "none";
+        if (show) { visible++; }
+      });
+      pkg.style.display = visible > 0 || (pkgMatch && tests.length === 0) ? "" : "none";
+    });
+  }
+  query.addEventListener("input", filter);
+  statuses.forEach(function (s) { s.addEventListener("change", filter); });
+})();
+</script>
+</body>
+</html>
+`
@@ -0,0 +1,55 @@
+package main
+
+import (
+	"bytes"
+	"html/template"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func Test_highlightGo(t *testing.T) {
+	t.Run("Highlight keywords, literals and comments", func(t *testing.T) {
+		src := "package a\n\n// f returns \"<1>\".\nfunc f() string { return `<1>\n` }\n"
+		want := []template.HTML{
+			`<span class="kw">package</span> a`,
+			``,
+			`<span class="com">// f returns &#34;&lt;1&gt;&#34;.</span>`,
+			`<span class="kw">func</span> f() string { <span class="kw">return</span> <span class="str">` + "`&lt;1&gt;" + `</span>`,
+			`<span class="str">` + "`" + `</span> }`,
+			``,
+		}
+		if diff := cmp.Diff(want, highlightGo([]byte(src))); diff != "" {
+			t.Errorf("highlightGo() mismatch (-want +got):\n%s", diff)
+		}
+	})
+}
+
+func Test_writeHTML(t *testing.T) {
+	t.Run("Write the test tree, failure messages and source context", func(t *testing.T) {
+		h := newHottestFromLog(t, "testdata/sample.json")
+		dirs := packageDirs{"example.com/sample/a": "testdata/sample/a"}
+
+		var b bytes.Buffer
+		if err := writeHTML(&b, h.newHTMLReport(dirs)); err != nil {
+			t.Fatal(err)
+		}
+		got := b.String()
+
+		for _, want := range []string{
+			`<details class="pkg" data-name="example.com/sample/a" open>`,
+			`<div class="test" data-name="TestFail/sub_ng" data-action="fail" style="margin-left: 2em">`,
+			`    a_test.go:10: got 1, want 2`,
+			`<span class="line hl"><span class="ln">10</span>		t.Errorf(<span class="str">&#34;got 1, want 2&#34;</span>)</span>`,
+			`<div class="test" data-name="TestSkip" data-action="skip" style="margin-left: 0em">`,
+		} {
+			if !strings.Contains(got, want) {
+				t.Errorf("HTML report does not contain %q", want)
+			}
+		}
+		if strings.Contains(got, "http://") || strings.Contains(got, "https://") {
+			t.Errorf("HTML report should not depend on external assets")
+		}
+	})
+}
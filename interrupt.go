@@ -0,0 +1,132 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"os/signal"
+	"sync"
+	"syscall"
+
+	"github.com/nao1215/hottest/testjson"
+)
+
+// interruptHandler handles SIGINT and SIGTERM while the test commands run. The first signal is
+// forwarded to the process groups of the running test commands so that they stop and report the
+// completed tests, and no more test commands are started. The second signal kills the process groups.
+// It is shared by the test commands of a run, including those of the children.
+type interruptHandler struct {
+	mu sync.Mutex
+	// cmds is the running test commands.
+	cmds map[*exec.Cmd]struct{}
+	// signals is the number of the received signals.
+	signals int
+	// w is the writer of the notices, e.g. os.Stderr.
+	w io.Writer
+
+	sigc chan os.Signal
+	done chan struct{}
+}
+
+// newInterruptHandler returns an interruptHandler that prints the notices to w.
+func newInterruptHandler(w io.Writer) *interruptHandler {
+	return &interruptHandler{cmds: map[*exec.Cmd]struct{}{}, w: w}
+}
+
+// start starts receiving SIGINT and SIGTERM. The other signals keep their default behavior.
+func (i *interruptHandler) start() {
+	i.sigc = make(chan os.Signal, 1)
+	i.done = make(chan struct{})
+	signal.Notify(i.sigc, os.Interrupt, syscall.SIGTERM)
+
+	go func() {
+		for {
+			select {
+			case sig := <-i.sigc:
+				i.handle(sig)
+			case <-i.done:
+				return
+			}
+		}
+	}()
+}
+
+// stop stops receiving the signals. After stop, the signals terminate hottest as usual.
+func (i *interruptHandler) stop() {
+	signal.Stop(i.sigc)
+	close(i.done)
+}
+
+// handle forwards the first signal to the running test commands and kills them on the second signal.
+func (i *interruptHandler) handle(sig os.Signal) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	i.signals++
+	if i.signals == 1 {
+		fmt.Fprintf(i.w, "\nhottest: %s: waiting for the running tests to stop (interrupt again to kill them)\n", sig)
+		for cmd := range i.cmds {
+			if err := signalProcessGroup(cmd, sig); err != nil && !errors.Is(err, os.ErrProcessDone) {
+				fmt.Fprintf(i.w, "failed to send signal: %s\n", err.Error())
+			}
+		}
+		return
+	}
+
+	fmt.Fprintf(i.w, "\nhottest: %s: killing the running tests\n", sig)
+	for cmd := range i.cmds {
+		if err := killProcessGroup(cmd); err != nil && !errors.Is(err, os.ErrProcessDone) {
+			fmt.Fprintf(i.w, "failed to kill the test command: %s\n", err.Error())
+		}
+	}
+}
+
+// add registers the started test command. If the run has been interrupted before the command is
+// registered, the interrupt is forwarded to it at once.
+func (i *interruptHandler) add(cmd *exec.Cmd) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	i.cmds[cmd] = struct{}{}
+	if i.signals == 0 {
+		return
+	}
+	if err := signalProcessGroup(cmd, os.Interrupt); err != nil && !errors.Is(err, os.ErrProcessDone) {
+		fmt.Fprintf(i.w, "failed to send signal: %s\n", err.Error())
+	}
+}
+
+// remove unregisters the test command that has finished.
+func (i *interruptHandler) remove(cmd *exec.Cmd) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	delete(i.cmds, cmd)
+}
+
+// interrupted returns true if a signal has been received. It returns false for a nil handler,
+// e.g. the hottest that reads the saved logs.
+func (i *interruptHandler) interrupted() bool {
+	if i == nil {
+		return false
+	}
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	return i.signals > 0
+}
+
+// printInterrupted prints the tests that were running when the run was interrupted, e.g. "TestX (example.com/a)".
+// The tests that had not started are not printed because go test does not report them.
+func printInterrupted(w io.Writer, r *testjson.Results) {
+	fmt.Fprintf(w, "[Interrupted]\n")
+	running := 0
+	for _, p := range r.Packages {
+		for _, t := range p.Unfinished() {
+			fmt.Fprintf(w, " %s%s (%s): running\n", t.Label(), t.Name, t.Package)
+			running++
+		}
+	}
+	if running == 0 {
+		fmt.Fprintf(w, " no tests were running\n")
+	}
+}
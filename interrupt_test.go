@@ -0,0 +1,124 @@
+package main
+
+import (
+	"bufio"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+	"syscall"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+// startCommand starts the shell script that is stopped by the interrupt handler.
+// It returns after the script prints "ready", e.g. after the script sets the trap.
+func startCommand(t *testing.T, script string) *exec.Cmd {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("signals cannot be sent to processes on Windows")
+	}
+
+	cmd := exec.Command("sh", "-c", script)
+	setProcessGroup(cmd)
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := cmd.Start(); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		killProcessGroup(cmd) //nolint
+	})
+	if _, err := bufio.NewReader(stdout).ReadString('\n'); err != nil {
+		t.Fatal(err)
+	}
+	return cmd
+}
+
+// waitSignal waits for the command and returns the signal that terminated it.
+func waitSignal(t *testing.T, cmd *exec.Cmd) syscall.Signal {
+	t.Helper()
+	cmd.Wait() //nolint
+	status, ok := cmd.ProcessState.Sys().(syscall.WaitStatus)
+	if !ok || !status.Signaled() {
+		t.Fatalf("the command is not terminated by a signal: %s", cmd.ProcessState)
+	}
+	return status.Signal()
+}
+
+func Test_interruptHandler(t *testing.T) {
+	t.Run("Forward the first signal to the running commands", func(t *testing.T) {
+		var b strings.Builder
+		i := newInterruptHandler(&b)
+		cmd := startCommand(t, "echo ready; exec sleep 10")
+		i.add(cmd)
+
+		i.handle(os.Interrupt)
+		if got := waitSignal(t, cmd); got != syscall.SIGINT {
+			t.Errorf("the command is terminated by %s, want %s", got, syscall.SIGINT)
+		}
+		if !i.interrupted() {
+			t.Errorf("interrupted() = false, want true")
+		}
+		if !strings.Contains(b.String(), "waiting for the running tests to stop") {
+			t.Errorf("the notice is not printed: %q", b.String())
+		}
+	})
+
+	t.Run("Kill the running commands on the second signal", func(t *testing.T) {
+		var b strings.Builder
+		i := newInterruptHandler(&b)
+		cmd := startCommand(t, "trap '' INT; echo ready; sleep 10")
+		i.add(cmd)
+
+		i.handle(os.Interrupt)
+		i.handle(os.Interrupt)
+		if got := waitSignal(t, cmd); got != syscall.SIGKILL {
+			t.Errorf("the command is terminated by %s, want %s", got, syscall.SIGKILL)
+		}
+		if !strings.Contains(b.String(), "killing the running tests") {
+			t.Errorf("the notice is not printed: %q", b.String())
+		}
+	})
+
+	t.Run("Forward the interrupt to the command that is added after the signal", func(t *testing.T) {
+		i := newInterruptHandler(&strings.Builder{})
+		i.handle(syscall.SIGTERM)
+
+		cmd := startCommand(t, "echo ready; exec sleep 10")
+		i.add(cmd)
+		if got := waitSignal(t, cmd); got != syscall.SIGINT {
+			t.Errorf("the command is terminated by %s, want %s", got, syscall.SIGINT)
+		}
+	})
+
+	t.Run("A nil handler is not interrupted", func(t *testing.T) {
+		var i *interruptHandler
+		if i.interrupted() {
+			t.Errorf("interrupted() = true, want false")
+		}
+	})
+}
+
+func Test_printInterrupted(t *testing.T) {
+	t.Run("Print the tests that were running", func(t *testing.T) {
+		var b strings.Builder
+		printInterrupted(&b, newHottestFromLog(t, "testdata/timeout.json").Results)
+		want := "[Interrupted]\n TestSlow (example.com/x/slow): running\n"
+		if diff := cmp.Diff(want, b.String()); diff != "" {
+			t.Errorf("printInterrupted() mismatch (-want +got):\n%s", diff)
+		}
+	})
+
+	t.Run("Print that no tests were running", func(t *testing.T) {
+		var b strings.Builder
+		printInterrupted(&b, newHottestFromLog(t, "testdata/skip.json").Results)
+		want := "[Interrupted]\n no tests were running\n"
+		if diff := cmp.Diff(want, b.String()); diff != "" {
+			t.Errorf("printInterrupted() mismatch (-want +got):\n%s", diff)
+		}
+	})
+}
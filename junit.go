@@ -0,0 +1,121 @@
+package main
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/nao1215/hottest/testjson"
+)
+
+// junitReportFile is the file name of the JUnit XML report.
+const junitReportFile = "hottest_junit.xml"
+
+// junitTestSuites is the root element of the JUnit XML report.
+type junitTestSuites struct {
+	XMLName    xml.Name         `xml:"testsuites"`
+	Tests      int              `xml:"tests,attr"`
+	Failures   int              `xml:"failures,attr"`
+	Skipped    int              `xml:"skipped,attr"`
+	Time       string           `xml:"time,attr"`
+	TestSuites []junitTestSuite `xml:"testsuite"`
+}
+
+// junitTestSuite is a package in the JUnit XML report.
+type junitTestSuite struct {
+	Name      string          `xml:"name,attr"`
+	Tests     int             `xml:"tests,attr"`
+	Failures  int             `xml:"failures,attr"`
+	Skipped   int             `xml:"skipped,attr"`
+	Time      string          `xml:"time,attr"`
+	TestCases []junitTestCase `xml:"testcase"`
+}
+
+// junitTestCase is a test in the JUnit XML report.
+type junitTestCase struct {
+	ClassName string        `xml:"classname,attr"`
+	Name      string        `xml:"name,attr"`
+	Time      string        `xml:"time,attr"`
+	Failure   *junitMessage `xml:"failure,omitempty"`
+	Skipped   *junitMessage `xml:"skipped,omitempty"`
+}
+
+// junitMessage is the failure or skip message of a test.
+type junitMessage struct {
+	Message  string `xml:"message,attr"`
+	Contents string `xml:",chardata"`
+}
+
+// writeJUnit writes the test results as a JUnit XML report to w.
+func writeJUnit(w io.Writer, r *testjson.Results, i *testjson.Interval) error {
+	suites := junitTestSuites{
+		Time:       junitTime(i.Duration().Seconds()),
+		TestSuites: make([]junitTestSuite, 0, len(r.Packages)),
+	}
+
+	for _, p := range r.Packages {
+		suite := junitTestSuite{
+			Name:      p.Key(),
+			Tests:     int(p.Stats.Total),
+			Failures:  int(p.Stats.Fail),
+			Skipped:   int(p.Stats.Skip),
+			Time:      junitTime(p.Elapsed),
+			TestCases: make([]junitTestCase, 0, len(p.Tests)),
+		}
+		for _, t := range p.Tests {
+			tc := junitTestCase{ClassName: p.Name, Name: t.Name, Time: junitTime(t.Elapsed)}
+			switch t.Action {
+			case testjson.ActionFail:
+				msgs := t.Messages()
+				tc.Failure = &junitMessage{Message: failureSummary(msgs), Contents: strings.Join(msgs, "\n")}
+			case testjson.ActionSkip:
+				tc.Skipped = &junitMessage{Message: t.SkipReason()}
+			}
+			suite.TestCases = append(suite.TestCases, tc)
+		}
+		suites.Tests += suite.Tests
+		suites.Failures += suite.Failures
+		suites.Skipped += suite.Skipped
+		suites.TestSuites = append(suites.TestSuites, suite)
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(suites); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+// writeJUnitFile writes the JUnit XML report to junitReportFile.
+func (h *hottest) writeJUnitFile() error {
+	f, err := os.Create(junitReportFile)
+	if err != nil {
+		return fmt.Errorf("failed to create %s: %w", junitReportFile, err)
+	}
+	defer f.Close() //nolint
+
+	if err := writeJUnit(f, h.Results, h.interval); err != nil {
+		return fmt.Errorf("failed to write %s: %w", junitReportFile, err)
+	}
+	return f.Close()
+}
+
+// failureSummary returns the first message of the failed test as the one line summary.
+func failureSummary(msgs []string) string {
+	if len(msgs) == 0 {
+		return "Failed"
+	}
+	return strings.TrimSpace(msgs[0])
+}
+
+// junitTime returns the elapsed seconds in the format of the JUnit XML report.
+func junitTime(sec float64) string {
+	return fmt.Sprintf("%.3f", sec)
+}
@@ -0,0 +1,42 @@
+package main
+
+import (
+	"bytes"
+	"encoding/xml"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func Test_writeJUnit(t *testing.T) {
+	t.Run("Write test results as JUnit XML", func(t *testing.T) {
+		h := newHottestFromLog(t, "testdata/sample.json")
+
+		var b bytes.Buffer
+		if err := writeJUnit(&b, h.Results, h.interval); err != nil {
+			t.Fatal(err)
+		}
+
+		var got junitTestSuites
+		if err := xml.Unmarshal(b.Bytes(), &got); err != nil {
+			t.Fatal(err)
+		}
+		if diff := cmp.Diff([]int{6, 2, 1}, []int{got.Tests, got.Failures, got.Skipped}); diff != "" {
+			t.Errorf("tests/failures/skipped mismatch (-want +got):\n%s", diff)
+		}
+		if len(got.TestSuites) != 2 {
+			t.Fatalf("got %d test suites, want 2", len(got.TestSuites))
+		}
+
+		var failure *junitMessage
+		for _, tc := range got.TestSuites[0].TestCases {
+			if tc.Name == "TestFail/sub_ng" {
+				failure = tc.Failure
+			}
+		}
+		want := &junitMessage{Message: "a_test.go:10: got 1, want 2", Contents: "    a_test.go:10: got 1, want 2"}
+		if diff := cmp.Diff(want, failure); diff != "" {
+			t.Errorf("failure mismatch (-want +got):\n%s", diff)
+		}
+	})
+}
@@ -0,0 +1,75 @@
+package main
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// locationRegexp matches the location that testing.T prefixes to a message, e.g. "main_test.go:25: ".
+var locationRegexp = regexp.MustCompile(`^\s*([\w.\-]+\.go):(\d+): `)
+
+// Location is a position in a source file.
+type Location struct {
+	// File is the file name, e.g. "main_test.go".
+	File string
+	// Line is the line number.
+	Line int
+}
+
+// findLocation returns the first location reported in the messages.
+// It returns false if no message has a location.
+func findLocation(msgs []string) (Location, bool) {
+	for _, msg := range msgs {
+		m := locationRegexp.FindStringSubmatch(msg)
+		if m == nil {
+			continue
+		}
+		line, err := strconv.Atoi(m[2])
+		if err != nil {
+			continue
+		}
+		return Location{File: m[1], Line: line}, true
+	}
+	return Location{}, false
+}
+
+// packageDirs resolves the import paths of packages to their directories.
+// It caches the result because it runs "go list" for each package.
+type packageDirs map[string]string
+
+// dir returns the directory of the package. It returns an empty string if the
+// package directory cannot be resolved.
+func (p packageDirs) dir(pkg string) string {
+	if dir, ok := p[pkg]; ok {
+		return dir
+	}
+	out, err := exec.Command("go", "list", "-f", "{{.Dir}}", pkg).Output() //#nosec
+	if err != nil {
+		p[pkg] = ""
+		return ""
+	}
+	p[pkg] = strings.TrimSpace(string(out))
+	return p[pkg]
+}
+
+// relPath returns the path of the file in the package relative to the current directory.
+// If the package directory cannot be resolved, it returns the file name as it is.
+func (p packageDirs) relPath(pkg, file string) string {
+	dir := p.dir(pkg)
+	if dir == "" {
+		return file
+	}
+	path := filepath.Join(dir, file)
+	wd, err := os.Getwd()
+	if err != nil {
+		return path
+	}
+	if rel, err := filepath.Rel(wd, path); err == nil {
+		return filepath.ToSlash(rel)
+	}
+	return path
+}
@@ -0,0 +1,47 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/nao1215/hottest/testjson"
+)
+
+// formatNone prints nothing for each test. It is used to read the saved logs.
+const formatNone = "none"
+
+// newHottestForLog returns a hottest that reads the saved 'go test -json' logs.
+func newHottestForLog() *hottest {
+	return &hottest{
+		opts:       &options{format: formatNone},
+		Aggregator: testjson.NewAggregator(),
+		interval:   testjson.NewInterval(),
+	}
+}
+
+// readLog reads the 'go test -json' log file with the same parser as the test run.
+// The interval of the returned hottest is the time of the first and the last test events.
+func readLog(path string) (*hottest, error) {
+	h := newHottestForLog()
+	if err := h.consumeLog(path); err != nil {
+		return nil, err
+	}
+	return h, nil
+}
+
+// consumeLog reads the 'go test -json' log file and adds the test results.
+// The interval is updated to the time of the earliest and the latest test events.
+func (h *hottest) consumeLog(path string) error {
+	f, err := os.Open(filepath.Clean(path))
+	if err != nil {
+		return fmt.Errorf("failed to open %s: %w", path, err)
+	}
+	defer f.Close() //nolint
+
+	h.consume(f)
+
+	h.interval.Started = h.Results.Started
+	h.interval.Finished = h.Results.Finished
+	return nil
+}
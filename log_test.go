@@ -0,0 +1,45 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/nao1215/hottest/testjson"
+)
+
+func Test_readLog(t *testing.T) {
+	t.Run("Read go test -json log", func(t *testing.T) {
+		h, err := readLog("testdata/sample.json")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if diff := cmp.Diff(testjson.TestStats{Pass: 3, Fail: 2, Skip: 1, Total: 6}, h.Stats); diff != "" {
+			t.Errorf("stats mismatch (-want +got):\n%s", diff)
+		}
+		want := time.Date(2026, 10, 19, 4, 35, 2, 445917626, time.UTC)
+		if !h.interval.Started.Equal(want) {
+			t.Errorf("started = %s, want %s", h.interval.Started, want)
+		}
+		if h.interval.Duration() <= 0 {
+			t.Errorf("duration = %s, want positive duration", h.interval.Duration())
+		}
+	})
+
+	t.Run("If the log file does not exist, return error", func(t *testing.T) {
+		if _, err := readLog("testdata/not_exist.json"); err == nil {
+			t.Error("readLog() should return error")
+		}
+	})
+}
+
+// newHottestFromLog returns a hottest that parsed the go test -json log in testdata.
+func newHottestFromLog(t *testing.T, path string) *hottest {
+	t.Helper()
+
+	h, err := readLog(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return h
+}
@@ -34,25 +34,18 @@ OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
 */
 
 import (
-	"bufio"
-	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
 	"os"
 	"os/exec"
-	"os/signal"
 	"strings"
-	"sync"
-	"sync/atomic"
 	"syscall"
-	"time"
 	"unicode"
 
 	"github.com/fatih/color"
-	"github.com/go-spectest/markdown"
+	"github.com/nao1215/hottest/testjson"
 	"github.com/nao1215/hottest/version"
-	"github.com/tenntenn/testtime"
 	"golang.org/x/exp/slices"
 )
 
@@ -63,23 +56,36 @@ func main() {
 	enableOnCI()
 
 	if err := run(os.Args); err != nil {
-		if errors.Is(err, errExitStatus) || errors.Is(err, errFailTest) {
-			osExit(1)
+		// The test results, including the interruption, have been printed in the summary.
+		if !errors.Is(err, errExitStatus) && !errors.Is(err, errFailTest) && !errors.Is(err, errInterrupted) {
+			fmt.Fprintln(os.Stderr, err.Error())
 		}
-		fmt.Fprintln(os.Stderr, err.Error())
-		osExit(1)
+		osExit(exitCode(err))
+		return
 	}
-	osExit(0)
+	osExit(exitCodeOK)
 }
 
 // run execute command.
 func run(args []string) error {
+	if len(args) >= 2 {
+		switch args[1] {
+		case "history":
+			return runHistory(args[2:])
+		case "diff":
+			return runDiffCommand(args[2:])
+		case "merge":
+			return runMerge(args[2:])
+		}
+	}
+
 	hottest, err := newHottest(args)
 	if err != nil {
 		if errors.Is(err, errNoArguments) {
 			usage()
 			return nil // ignore error
 		}
+		return err
 	}
 	return hottest.run()
 }
@@ -90,30 +96,40 @@ func usage() {
 	fmt.Println("User-friendly 'go test' that extracts error messages.")
 	fmt.Println("")
 	fmt.Println("Usage:")
-	fmt.Println("  hottest [arguments]")
+	fmt.Println("  hottest [options] [arguments]")
 	fmt.Println("          ※ The arguments are the same as 'go test'.")
+	fmt.Println("  hottest history [-dir dir] [-n runs] [-threshold ratio]")
+	fmt.Println("          ※ Compare the latest run with the previous runs saved by -hottest.history.")
+	fmt.Println("  hottest diff [-threshold rate] old.json new.json")
+	fmt.Println("          ※ Compare the test results of two 'go test -json' logs.")
+	fmt.Println("  hottest merge [-markdown file] [label=]a.json [label=]b.json ...")
+	fmt.Println("          ※ Summarize the test results of multiple 'go test -json' logs (e.g. shards, OSes).")
+	fmt.Println("")
+	optionsUsage()
+	fmt.Println("")
 	fmt.Println("Example:")
 	fmt.Println("  hottest -cover ./... -coverprofile=cover.out")
-}
-
-// TestStats holds the test statistics.
-type TestStats struct {
-	// Pass is the number of passed tests.
-	Pass int32
-	// Fail is the number of failed tests.
-	Fail int32
-	// Skip is the number of skipped tests.
-	Skip int32
-	// Total is the number of total tests.
-	Total int32
+	fmt.Println("  hottest -hottest.format=teamcity ./...")
+	fmt.Println("  hottest -hottest.shard=1/3 -hottest.log=shard1.json ./...")
+	fmt.Println("  hottest -hottest.matrix=matrix.json ./...")
+	fmt.Println("  hottest -hottest.reporter='python3 notify.py' ./...")
 }
 
 // hottest is a struct for hottest command.
 type hottest struct {
-	args            []string
-	stats           TestStats
-	allTestMessages []string
-	interval        *Interval
+	args      []string
+	opts      *options
+	reporters []Reporter
+	// Aggregator holds the test statistics, the output and the results of the tests.
+	*testjson.Aggregator
+	// matrix is the configurations of the matrix run. If it is empty, the tests are run once.
+	matrix []matrixConfig
+	// env is the environment variables added to the test command.
+	env      []string
+	interval *testjson.Interval
+	log      io.Writer
+	// interrupt handles the signals while the test commands run. It is nil if no tests are run.
+	interrupt *interruptHandler
 }
 
 var (
@@ -131,29 +147,150 @@ func newHottest(args []string) (*hottest, error) {
 		return nil, errNoArguments
 	}
 
+	opts, goTestArgs, err := parseOptions(args[1:])
+	if err != nil {
+		return nil, err
+	}
+
+	h := &hottest{
+		args:       goTestArgs,
+		opts:       opts,
+		Aggregator: testjson.NewAggregator(),
+		interval:   testjson.NewInterval(),
+		interrupt:  newInterruptHandler(os.Stderr),
+	}
+	switch opts.format {
+	case formatDots:
+		h.reporters = append(h.reporters, dotReporter{w: os.Stdout})
+	case formatTeamCity:
+		h.reporters = append(h.reporters, newTeamCityWriter(os.Stdout))
+	}
+	return h, nil
+}
+
+// newChild returns a hottest that runs a test command with the additional environment variables.
+// The child has its own test statistics, messages and results, and shares the options and the
+// output writers with h. Its results are added to h by add after the test command finishes.
+func (h *hottest) newChild(env []string) *hottest {
 	return &hottest{
-		args:            args[1:],
-		stats:           TestStats{},
-		allTestMessages: []string{},
-		interval:        NewInterval(),
-	}, nil
+		opts:       h.opts,
+		reporters:  h.reporters,
+		env:        env,
+		Aggregator: testjson.NewAggregator(),
+		interval:   testjson.NewInterval(),
+		log:        h.log,
+		interrupt:  h.interrupt,
+	}
 }
 
 // run runs the hottest command.
 func (h *hottest) run() error {
 	if err := h.canUseGoCommand(); err != nil {
-		return errors.New("hottest command requires go command. please install go command")
+		return errGoNotFound
+	}
+	if h.opts.changed != "" {
+		args, ok, err := selectChangedPackages(h.args, h.opts.changed)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			reason := fmt.Sprintf("no packages are affected by the changes from %s", h.opts.changed)
+			fmt.Fprintln(os.Stdout, reason)
+			return h.checkNoTestsToRun(reason)
+		}
+		h.args = args
+	}
+	if h.opts.matrix != "" {
+		matrix, err := loadMatrixConfigs(h.opts.matrix)
+		if err != nil {
+			return err
+		}
+		h.matrix = matrix
+	}
+
+	if h.opts.log != "" {
+		f, err := os.Create(h.opts.log)
+		if err != nil {
+			return fmt.Errorf("failed to create %s: %w", h.opts.log, err)
+		}
+		defer f.Close() //nolint
+		h.log = f
+	}
+
+	invocations := [][]string{h.args}
+	if h.opts.shard != "" {
+		var err error
+		if invocations, err = shardInvocations(h.args, h.opts); err != nil {
+			return err
+		}
+		if len(invocations) == 0 {
+			reason := fmt.Sprintf("no tests in shard %s", h.opts.shard)
+			fmt.Fprintln(os.Stdout, reason)
+			return h.checkNoTestsToRun(reason)
+		}
+	}
+
+	parallel := h.opts.groups != "" || h.opts.parallel > 1
+	var groups []testGroup
+	if parallel {
+		var err error
+		if groups, err = h.testGroups(); err != nil {
+			return err
+		}
+	}
+
+	if h.opts.notifyWebhook != "" {
+		r, err := newWebhookReporter(h.opts.notifyWebhook, h.opts.notifyFormat, h.opts.notifyTemplate)
+		if err != nil {
+			return err
+		}
+		h.reporters = append(h.reporters, r)
 	}
-	if err := h.runTest(); err != nil {
-		h.testResult()
+	reporters, err := newExternalReporters(h.opts.reporters)
+	if err != nil {
 		return err
 	}
+	for _, r := range reporters {
+		h.reporters = append(h.reporters, r)
+	}
+	h.startReporters()
 
+	h.interrupt.start()
+	switch {
+	case len(h.matrix) > 0:
+		err = h.runMatrix(invocations)
+	case parallel:
+		err = h.runGroups(groups, h.opts.parallel)
+	default:
+		err = h.runTests(invocations)
+	}
+	h.interrupt.stop()
+	if h.interrupt.interrupted() {
+		// The error of the interrupted test command may be the exit status, so it is replaced.
+		err = errInterrupted
+	}
 	h.testResult()
-	if h.stats.Fail > 0 {
-		return errFailTest
+	benchErr := h.compareBenchBaselineIfNeeded(err == nil && h.Stats.Fail == 0)
+	h.saveHistoryIfNeeded()
+	err = h.testError(err)
+	if err == nil {
+		err = h.checkPolicy()
+	}
+	if err == nil {
+		err = benchErr
+	}
+	h.finishReporters(err)
+	return err
+}
+
+// saveHistoryIfNeeded saves the test results to the history directory if -hottest.history is specified.
+func (h *hottest) saveHistoryIfNeeded() {
+	if h.opts.history == "" {
+		return
+	}
+	if err := h.saveHistory(h.opts.history); err != nil {
+		fmt.Fprintln(os.Stderr, err.Error())
 	}
-	return nil
 }
 
 // canUseGoCommand returns true if go command is available.
@@ -162,16 +299,47 @@ func (h *hottest) canUseGoCommand() error {
 	return err
 }
 
-// runTest runs the test command.
-func (h *hottest) runTest() error {
-	var wg sync.WaitGroup
-	wg.Add(1)
-	defer wg.Wait()
+// runTests runs the test command for each argument list in order.
+// Even if a test command fails, the remaining test commands are run unless the run is interrupted.
+// It returns the first error.
+func (h *hottest) runTests(invocations [][]string) error {
+	h.interval.Start()
+	defer h.interval.End()
+
+	var firstErr error
+	for _, args := range invocations {
+		if err := h.runTest(args); err != nil && firstErr == nil {
+			firstErr = err
+		}
+		if h.interrupt.interrupted() {
+			break
+		}
+	}
+	return firstErr
+}
+
+// runTest runs the test command with the arguments.
+// The output is consumed until the end of the stream before it returns,
+// so the results are complete when it returns.
+func (h *hottest) runTest(testArgs []string) error {
+	if h.interrupt.interrupted() {
+		return errInterrupted
+	}
 
-	r, w := io.Pipe()
-	defer w.Close() //nolint
+	// The output is written to an OS pipe instead of an io.Pipe, so that cmd.Wait does not wait for
+	// the leftover processes that hold the output. The reader reaches the end after they are killed.
+	r, w, err := os.Pipe()
+	if err != nil {
+		return fmt.Errorf("failed to create pipe: %w", err)
+	}
+	consumed := make(chan struct{})
+	defer func() {
+		w.Close() //nolint
+		<-consumed
+		r.Close() //nolint
+	}()
 
-	args := append([]string{"test"}, h.args...)
+	args := append([]string{"test"}, testArgs...)
 	if !slices.Contains(args, "-v") {
 		args = append(args, "-v") // This option is required to count the number of tests.
 	}
@@ -182,41 +350,28 @@ func (h *hottest) runTest() error {
 	cmd := exec.Command("go", args...) //#nosec
 	cmd.Stderr = w
 	cmd.Stdout = w
-	cmd.Env = os.Environ()
-
-	h.interval.Start()
-	defer h.interval.End()
-	if err := cmd.Start(); err != nil {
-		wg.Done()
-		return err
-	}
-
-	go h.consume(&wg, r)
-
-	sigc := make(chan os.Signal, 1)
-	done := make(chan struct{})
-	defer func() {
-		done <- struct{}{}
-	}()
-	signal.Notify(sigc)
+	cmd.Env = append(os.Environ(), h.env...)
+	setProcessGroup(cmd)
 
 	go func() {
-		for {
-			select {
-			case sig := <-sigc:
-				if err := cmd.Process.Signal(sig); err != nil {
-					if errors.Is(err, os.ErrProcessDone) {
-						break
-					}
-					fmt.Fprintf(os.Stderr, "failed to send signal: %s", err.Error())
-				}
-			case <-done:
-				return
-			}
-		}
+		defer close(consumed)
+		h.consume(r)
 	}()
+	err = cmd.Start()
+	// The test command has its own copy of the writer.
+	w.Close() //nolint
+	if err != nil {
+		return err
+	}
 
-	if err := cmd.Wait(); err != nil {
+	h.interrupt.add(cmd)
+	err = cmd.Wait()
+	h.interrupt.remove(cmd)
+	cleanupProcessGroup(os.Stderr, cmd)
+	if h.interrupt.interrupted() {
+		return errInterrupted
+	}
+	if err != nil {
 		if _, ok := cmd.ProcessState.Sys().(syscall.WaitStatus); ok {
 			return errExitStatus
 		}
@@ -225,223 +380,94 @@ func (h *hottest) runTest() error {
 	return nil
 }
 
-// consume consumes the output of the test command.
-func (h *hottest) consume(wg *sync.WaitGroup, r io.Reader) {
-	defer wg.Done()
-	reader := bufio.NewReader(r)
-	for {
-		l, _, err := reader.ReadLine()
-		if err == io.EOF {
-			return
-		}
-		if err != nil {
-			fmt.Fprintln(os.Stderr, err.Error())
-			return // If an error occurs, the goroutine is not stopped.
-		}
-		if err := h.parse(string(l)); err != nil {
-			fmt.Fprintln(os.Stderr, err.Error())
-		}
-	}
-}
-
-// TestOutputJSON represents the structure of a test output log entry.
-type TestOutputJSON struct {
-	Time    time.Time `json:"Time"`
-	Action  string    `json:"Action"`
-	Package string    `json:"Package"`
-	Test    string    `json:"Test"`
-	Output  string    `json:"Output,omitempty"`
-	Elapsed float64   `json:"Elapsed,omitempty"`
-}
-
-// parse parses a line of test output. It updates the test statistics.
-func (h *hottest) parse(line string) error {
-	var outputJSON TestOutputJSON
-	if err := json.Unmarshal([]byte(line), &outputJSON); err != nil {
-		// If the line is not a JSON, hottest has bad arguments.
-		// So, line is likely to be an error message:
-		// 'package test is not in std (/usr/local/go/src/test)'
-		return errors.New(line)
-	}
-	trimmed := strings.TrimSpace(outputJSON.Output)
-
-	switch {
-	case strings.HasPrefix(trimmed, "ok"):
-		fallthrough
-	case strings.HasPrefix(trimmed, "FAIL"):
-		fallthrough
-	case strings.HasPrefix(trimmed, "PASS"):
-		fallthrough
-	case strings.Contains(trimmed, "[no test files]"):
-		return nil
-
-	case strings.HasPrefix(trimmed, "=== RUN"):
-		fallthrough
-	case strings.HasPrefix(trimmed, "=== CONT"):
-		fallthrough
-	case strings.HasPrefix(trimmed, "=== PAUSE"):
-		h.allTestMessages = append(h.allTestMessages, strings.TrimRightFunc(outputJSON.Output, unicode.IsSpace))
-		return nil
-
-	// passed
-	case strings.HasPrefix(trimmed, "--- PASS"):
-		fmt.Fprint(os.Stdout, color.GreenString("."))
-		atomic.AddInt32(&h.stats.Pass, 1)
-		atomic.StoreInt32(&h.stats.Total, atomic.AddInt32(&h.stats.Total, 1))
-		h.allTestMessages = append(h.allTestMessages, strings.TrimRightFunc(outputJSON.Output, unicode.IsSpace))
-
-	// skipped
-	case strings.HasPrefix(trimmed, "--- SKIP"):
-		fmt.Fprint(os.Stdout, color.BlueString("."))
-		atomic.AddInt32(&h.stats.Skip, 1)
-		atomic.StoreInt32(&h.stats.Total, atomic.AddInt32(&h.stats.Total, 1))
-		h.allTestMessages = append(h.allTestMessages, strings.TrimRightFunc(outputJSON.Output, unicode.IsSpace))
-
-	// failed
-	case strings.HasPrefix(trimmed, "--- FAIL"):
-		fmt.Fprint(os.Stdout, color.RedString("."))
-		atomic.AddInt32(&h.stats.Fail, 1)
-		atomic.StoreInt32(&h.stats.Total, atomic.AddInt32(&h.stats.Total, 1))
-		h.allTestMessages = append(h.allTestMessages, strings.TrimRightFunc(outputJSON.Output, unicode.IsSpace))
-
-	default:
-		h.allTestMessages = append(h.allTestMessages, strings.TrimRightFunc(outputJSON.Output, unicode.IsSpace))
-		return nil
-	}
-	return nil
-}
-
 // testResult prints the test result.
 func (h *hottest) testResult() {
-	if h.stats.Total == 0 {
+	benchmarks := h.Results.Benchmarks()
+	interrupted := h.interrupt.interrupted()
+	if h.Stats.Total == 0 && len(benchmarks) == 0 && !hasFailedPackage(h.Results) && !interrupted {
 		fmt.Fprintf(os.Stdout, "no tests to run\n")
 		return
 	}
 
 	fmt.Fprintln(os.Stdout)
 
-	if h.stats.Fail > 0 {
-		fmt.Fprintf(os.Stdout, "[Error Messages]\n")
-		for _, msg := range extractFailTestMessage(h.allTestMessages) {
-			fmt.Fprintf(os.Stdout, " %s\n", strings.TrimRightFunc(msg, unicode.IsSpace))
-		}
-	}
-
-	fmt.Fprintf(os.Stdout, "Results: %s/%s/%s (%s/%s/%s, %s)\n",
-		color.GreenString("%d", h.stats.Pass), color.RedString("%d", h.stats.Fail), color.BlueString("%d", h.stats.Skip),
-		color.GreenString("%s", "ok"), color.RedString("%s", "ng"), color.BlueString("%s", "skip"),
-		h.interval.Duration())
-
-	h.generateTestResultMarkdownOnGitHubActions()
-}
-
-// generateTestResultMarkdownOnGitHubActions generates the test result markdown on GitHub Actions.
-func (h *hottest) generateTestResultMarkdownOnGitHubActions() {
-	if os.Getenv("GITHUB_ACTIONS") != "true" {
-		return
+	if h.Stats.Fail > 0 {
+		h.printErrorMessages(os.Stdout)
 	}
 
-	f, err := os.Create("hottest_report.md")
-	if err != nil {
-		fmt.Fprintf(os.Stderr, "failed to create hottest_report.md: %s", err.Error())
-		return
+	printSkipped(os.Stdout, h.Results, h.opts.groupSkips)
+	printFuzz(os.Stdout, h.Results)
+	if len(h.matrix) > 0 {
+		printMatrix(os.Stdout, h.Results, h.matrix)
 	}
-	defer func() {
-		if err := f.Close(); err != nil {
-			fmt.Fprintf(os.Stderr, "failed to close hottest_report.md: %s", err.Error())
-		}
-	}()
-
-	md := markdown.NewMarkdown(f).
-		H2("HOTTEST report").
-		Table(markdown.TableSet{
-			Header: []string{"PASS", "FAIL", "SKIP", "TOTAL", "DURATION"},
-			Rows: [][]string{
-				{
-					fmt.Sprintf("%d", h.stats.Pass),
-					fmt.Sprintf("%d", h.stats.Fail),
-					fmt.Sprintf("%d", h.stats.Skip),
-					fmt.Sprintf("%d", h.stats.Total),
-					h.interval.Duration().String(),
-				},
-			},
-		})
-
-	if h.stats.Fail > 0 {
-		color.NoColor = true
-		md = md.H2("Error Messages").
-			CodeBlocks(markdown.SyntaxHighlightText, strings.Join(extractFailTestMessage(h.allTestMessages), "\n"))
-		color.NoColor = false
+	printBenchmarks(os.Stdout, benchmarks)
+	if interrupted {
+		printInterrupted(os.Stdout, h.Results)
+		fmt.Fprintf(os.Stdout, "Results: %s %s\n", h.statsString(), color.YellowString("interrupted"))
+	} else {
+		fmt.Fprintf(os.Stdout, "Results: %s\n", h.statsString())
 	}
 
-	err = md.HorizontalRule().LF().
-		PlainTextf("Reported by %s", markdown.Link("hottest", "https://github.com/nao1215/hottest")).Build()
+	h.reportToCI()
 
-	if err != nil {
-		fmt.Fprintf(os.Stderr, "failed to write markdown report: %s", err.Error())
-		return
+	if h.opts.html != "" {
+		if err := h.writeHTMLFile(h.opts.html); err != nil {
+			fmt.Fprintln(os.Stderr, err.Error())
+		}
 	}
 }
 
-// extractFailTestMessage extracts the error message of the failed test.
-func extractFailTestMessage(testResultMsgs []string) []string {
-	failTestMessages := []string{}
-	beforeRunPos := 0
-	lastFailPos := 0
-	lastRunMsg := ""
-
-	for i, msg := range testResultMsgs {
-		switch {
-		case strings.Contains(msg, "=== RUN"):
-			if lastRunMsg != "" && strings.Contains(msg, fmt.Sprintf("%s/", lastRunMsg)) {
-				continue
-			}
-
-			if beforeRunPos < lastFailPos {
-				for _, v := range testResultMsgs[beforeRunPos:lastFailPos] {
-					if isRecordableErrorMessage(v) {
-						failTestMessages = append(failTestMessages, fmt.Sprintf("    %s", color.RedString(v)))
-					}
-				}
-			}
-			lastRunMsg = extractStringBeforeThrash(msg)
-			beforeRunPos = i
-		case strings.Contains(msg, "--- FAIL"):
-			lastFailPos = i
-			failTestMessages = append(failTestMessages, msg)
-		default:
+// hasFailedPackage returns true if a package failed, e.g. it failed to build or timed out before any test finished.
+func hasFailedPackage(r *testjson.Results) bool {
+	for _, p := range r.Packages {
+		if p.Action == testjson.ActionFail {
+			return true
 		}
 	}
+	return false
+}
 
-	if beforeRunPos < lastFailPos {
-		for _, v := range testResultMsgs[beforeRunPos:lastFailPos] {
-			if isRecordableErrorMessage(v) {
-				failTestMessages = append(failTestMessages, fmt.Sprintf("    %s", color.RedString(v)))
+// printErrorMessages prints the error messages of the failed tests. The messages are red, and the expected
+// and the actual values of the recognized assertion formats are highlighted by messageHighlighter.
+// The diff between the expected and the actual output is printed after the "--- FAIL" line of a failed example.
+func (h *hottest) printErrorMessages(w io.Writer) {
+	examples := failedExamples(h.Results)
+	highlighter := &messageHighlighter{}
+
+	fmt.Fprintf(w, "[Error Messages]\n")
+	for _, msg := range withFuzzInputs(h.FailMessages(), h.Results.FuzzFailures(), packageDirs{}) {
+		msg = strings.TrimRightFunc(msg, unicode.IsSpace)
+		if !strings.Contains(msg, "--- FAIL") {
+			// The error messages are indented by four spaces.
+			if highlighted, ok := highlighter.highlight(strings.TrimPrefix(msg, "    ")); ok {
+				fmt.Fprintf(w, "     %s\n", highlighted)
 			}
+			continue
 		}
-	}
-	return failTestMessages
-}
+		*highlighter = messageHighlighter{}
+		fmt.Fprintf(w, " %s\n", msg)
 
-// extractStringBeforeThrash extracts the string before the slash.
-func extractStringBeforeThrash(s string) string {
-	if index := strings.Index(s, "/"); index != -1 {
-		return s[:index]
+		key := failedExampleKey(msg)
+		if len(examples[key]) == 0 {
+			continue
+		}
+		example := examples[key][0]
+		examples[key] = examples[key][1:]
+		if diff, ok := exampleDiff(example); ok {
+			fmt.Fprintf(w, "     (-want +got)\n")
+			for _, d := range diff {
+				fmt.Fprintf(w, "     %s\n", d.colored())
+			}
+		}
 	}
-	return s
 }
 
-// isRecordableErrorMessage returns true if the string is a recordable error message.
-func isRecordableErrorMessage(s string) bool {
-	return !strings.Contains(s, "--- FAIL") &&
-		!strings.Contains(s, "--- PASS") &&
-		!strings.Contains(s, "--- SKIP") &&
-		!strings.Contains(s, "=== RUN") &&
-		!strings.Contains(s, "=== CONT") &&
-		!strings.Contains(s, "=== PAUSE") &&
-		!strings.Contains(s, "=== CONT") &&
-		!strings.Contains(s, "=== NAME") &&
-		strings.TrimRightFunc(s, unicode.IsSpace) != ""
+// statsString returns the test statistics, e.g. "61/2/0 (ok/ng/skip, 242.172244ms)".
+func (h *hottest) statsString() string {
+	return fmt.Sprintf("%s/%s/%s (%s/%s/%s, %s)",
+		color.GreenString("%d", h.Stats.Pass), color.RedString("%d", h.Stats.Fail), color.BlueString("%d", h.Stats.Skip),
+		color.GreenString("%s", "ok"), color.RedString("%s", "ng"), color.BlueString("%s", "skip"),
+		h.interval.Duration())
 }
 
 // enableOnCI enables color on CI.
@@ -460,32 +486,3 @@ func enableOnCI() {
 		color.NoColor = false
 	}
 }
-
-// Interval represents a time interval.
-type Interval struct {
-	// Started is the Started time of the interval.
-	Started time.Time
-	// Finished is the Finished time of the interval.
-	Finished time.Time
-}
-
-// NewInterval creates a new interval.
-// This method is not set the start and end time of the interval.
-func NewInterval() *Interval {
-	return &Interval{}
-}
-
-// Start sets the start time of the interval.
-func (i *Interval) Start() {
-	i.Started = testtime.Now()
-}
-
-// End sets the end time of the interval.
-func (i *Interval) End() {
-	i.Finished = testtime.Now()
-}
-
-// Duration returns the duration of the interval.
-func (i Interval) Duration() time.Duration {
-	return i.Finished.Sub(i.Started)
-}
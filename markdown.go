@@ -0,0 +1,173 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/go-spectest/markdown"
+	"github.com/nao1215/hottest/testjson"
+)
+
+// slowestTestsNum is the number of tests shown in the slowest tests table.
+const slowestTestsNum = 10
+
+// writeMarkdownFile writes the test result markdown to the file.
+func (h *hottest) writeMarkdownFile(path string) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to create %s: %w", path, err)
+	}
+	defer f.Close() //nolint
+
+	if err := h.writeMarkdown(f); err != nil {
+		return fmt.Errorf("failed to write markdown report: %w", err)
+	}
+	return f.Close()
+}
+
+// writeMarkdown writes the test result markdown to w.
+func (h *hottest) writeMarkdown(w io.Writer) error {
+	md := markdown.NewMarkdown(w).
+		H2("HOTTEST report").
+		Table(markdown.TableSet{
+			Header: []string{"PASS", "FAIL", "SKIP", "TOTAL", "DURATION"},
+			Rows: [][]string{
+				{
+					fmt.Sprintf("%d", h.Stats.Pass),
+					fmt.Sprintf("%d", h.Stats.Fail),
+					fmt.Sprintf("%d", h.Stats.Skip),
+					fmt.Sprintf("%d", h.Stats.Total),
+					h.interval.Duration().String(),
+				},
+			},
+		})
+
+	if len(h.Results.Packages) > 0 {
+		md = md.H3("Packages").Table(packageTable(h.Results))
+	}
+
+	if rows := matrixRows(h.Results, h.matrix); len(h.matrix) > 0 && len(rows) > 0 {
+		md = md.H3("Matrix").Table(matrixTable(rows, h.matrix))
+	}
+
+	if benchmarks := h.Results.Benchmarks(); len(benchmarks) > 0 {
+		md = md.H3("Benchmarks").Table(benchmarkTable(benchmarks))
+	}
+
+	if failures := h.Results.Failures(); len(failures) > 0 {
+		md = md.H3("Failed tests")
+		for _, f := range failures {
+			md = md.Details(
+				fmt.Sprintf("%s%s %s (%s)", f.Label(), markdown.Code(f.Package), f.Name, elapsedString(f.Elapsed)),
+				failureDetails(f))
+		}
+		md = md.LF()
+	}
+
+	if skipped := h.Results.Tests(testjson.ActionSkip); len(skipped) > 0 {
+		md = md.H3("Skipped tests")
+		if h.opts.groupSkips {
+			for _, g := range groupSkippedTests(skipped) {
+				md = md.H4f("%s (%d)", g.reason, len(g.tests))
+				for _, s := range g.tests {
+					md = md.BulletList(skippedTestMarkdown(s))
+				}
+				md = md.LF()
+			}
+		} else {
+			for _, s := range skipped {
+				md = md.BulletList(fmt.Sprintf("%s%s %s: %s", s.Label(), markdown.Code(s.Package), s.Name, skipReason(s)))
+			}
+			md = md.LF()
+		}
+	}
+
+	if slowest := h.Results.Slowest(slowestTestsNum); len(slowest) > 0 {
+		rows := make([][]string, 0, len(slowest))
+		for _, s := range slowest {
+			rows = append(rows, []string{s.Label() + s.Name, s.Package, elapsedString(s.Elapsed)})
+		}
+		md = md.H3("Slowest tests").Table(markdown.TableSet{
+			Header: []string{"TEST", "PACKAGE", "DURATION"},
+			Rows:   rows,
+		})
+	}
+
+	return md.HorizontalRule().LF().
+		PlainTextf("Reported by %s", markdown.Link("hottest", "https://github.com/nao1215/hottest")).Build()
+}
+
+// failureDetails returns the code block of the error messages of the failed test.
+// For a failed example, it returns the diff block between the expected and the actual output.
+func failureDetails(t *testjson.TestResult) string {
+	if diff, ok := exampleDiff(t); ok {
+		lines := make([]string, 0, len(diff))
+		for _, d := range diff {
+			lines = append(lines, d.String())
+		}
+		return fmt.Sprintf("\n```diff\n%s\n```", strings.Join(lines, "\n"))
+	}
+	return fmt.Sprintf("\n```text\n%s\n```", strings.Join(t.Messages(), "\n"))
+}
+
+// skippedTestMarkdown returns the list item of the skipped test in the group of its reason,
+// e.g. "`example.com/a` TestX (a_test.go:15)".
+func skippedTestMarkdown(t *testjson.TestResult) string {
+	if _, loc := skipLocation(t); loc != "" {
+		return fmt.Sprintf("%s%s %s (%s)", t.Label(), markdown.Code(t.Package), t.Name, loc)
+	}
+	return fmt.Sprintf("%s%s %s", t.Label(), markdown.Code(t.Package), t.Name)
+}
+
+// packageTable returns the per-package results table.
+// If the results are merged from multiple logs, the SOURCE column is added.
+func packageTable(r *testjson.Results) markdown.TableSet {
+	sources := r.HasSources()
+	rows := make([][]string, 0, len(r.Packages))
+	for _, p := range r.Packages {
+		row := []string{
+			p.Name,
+			strings.ToUpper(p.Action),
+			fmt.Sprintf("%d", p.Stats.Pass),
+			fmt.Sprintf("%d", p.Stats.Fail),
+			fmt.Sprintf("%d", p.Stats.Skip),
+			elapsedString(p.Elapsed),
+		}
+		if sources {
+			row = append([]string{p.Source}, row...)
+		}
+		rows = append(rows, row)
+	}
+	header := []string{"PACKAGE", "RESULT", "PASS", "FAIL", "SKIP", "DURATION"}
+	if sources {
+		header = append([]string{"SOURCE"}, header...)
+	}
+	return markdown.TableSet{Header: header, Rows: rows}
+}
+
+// matrixTable returns the table of the failed tests and their results under each configuration.
+func matrixTable(rows []matrixRow, configs []matrixConfig) markdown.TableSet {
+	header := []string{"TEST"}
+	for _, c := range configs {
+		header = append(header, c.Name)
+	}
+	header = append(header, "PACKAGE")
+
+	table := make([][]string, 0, len(rows))
+	for _, row := range rows {
+		cells := []string{row.name}
+		for _, a := range row.actions {
+			cells = append(cells, strings.ToUpper(a))
+		}
+		table = append(table, append(cells, row.pkg))
+	}
+	return markdown.TableSet{Header: header, Rows: table}
+}
+
+// elapsedString returns the elapsed seconds reported by go test as a duration string.
+func elapsedString(sec float64) string {
+	return time.Duration(sec * float64(time.Second)).Round(time.Millisecond).String()
+}
@@ -0,0 +1,71 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func Test_writeMarkdown(t *testing.T) {
+	t.Run("Write per-package table, failed tests, skipped tests and slowest tests", func(t *testing.T) {
+		h := newHottestFromLog(t, "testdata/sample.json")
+
+		var b strings.Builder
+		if err := h.writeMarkdown(&b); err != nil {
+			t.Fatal(err)
+		}
+		got := b.String()
+
+		for _, want := range []string{
+			"## HOTTEST report",
+			"### Packages",
+			"| example.com/sample/a | FAIL   |    2 |    2 |    1 |",
+			"### Failed tests",
+			"<details><summary>`example.com/sample/a` TestFail/sub_ng (0s)</summary>",
+			"    a_test.go:10: got 1, want 2",
+			"### Skipped tests",
+			"- `example.com/sample/a` TestSkip: a_test.go:15: not supported on this platform",
+			"### Slowest tests",
+		} {
+			if !strings.Contains(got, want) {
+				t.Errorf("markdown does not contain %q\n%s", want, got)
+			}
+		}
+		if strings.Contains(got, "TestFail (0s)") {
+			t.Errorf("markdown should not contain the parent test that fails only because of its subtests\n%s", got)
+		}
+	})
+
+	t.Run("Group the skipped tests by reason", func(t *testing.T) {
+		h := newHottestFromLog(t, "testdata/skip.json")
+		h.opts.groupSkips = true
+
+		var b strings.Builder
+		if err := h.writeMarkdown(&b); err != nil {
+			t.Fatal(err)
+		}
+		got := b.String()
+
+		for _, want := range []string{
+			"#### DATABASE_URL is not set (2)",
+			"- `example.com/sample/skip` TestDB (skip_test.go:10)",
+			"- `example.com/sample/skip` TestCache (skip_test.go:16)",
+			"#### no reason given (1)",
+			"- `example.com/sample/skip` TestNoReason",
+		} {
+			if !strings.Contains(got, want) {
+				t.Errorf("markdown does not contain %q\n%s", want, got)
+			}
+		}
+	})
+}
+
+func Test_failureDetails(t *testing.T) {
+	t.Run("Write the diff block of the failed example", func(t *testing.T) {
+		h := newHottestFromLog(t, "testdata/example.json")
+		got := failureDetails(h.Results.Find("example.com/sample/example", "Example_hello"))
+		want := "\n```diff\n  hello\n- world\n+ wrld\n  !\n```"
+		if got != want {
+			t.Errorf("failureDetails() = %q, want %q", got, want)
+		}
+	})
+}
@@ -0,0 +1,250 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/nao1215/hottest/testjson"
+)
+
+// matrixNotRun is the cell of the matrix table for a test that was not run in the configuration.
+const matrixNotRun = "-"
+
+// matrixConfig is a configuration of the matrix run.
+type matrixConfig struct {
+	// Name is the label of the configuration that is shown in the results.
+	Name string `json:"name"`
+	// Flags is the 'go test' flags added to the arguments, e.g. ["-race"].
+	Flags []string `json:"flags"`
+	// Tags is the build tags, e.g. "integration".
+	Tags string `json:"tags"`
+	// Env is the environment variables, e.g. {"CGO_ENABLED": "0"}.
+	Env map[string]string `json:"env"`
+	// GoFlags is the value of the GOFLAGS environment variable, e.g. "-mod=mod".
+	GoFlags string `json:"goflags"`
+}
+
+// matrixFile is the structure of the matrix configuration file.
+type matrixFile struct {
+	Configs []matrixConfig `json:"configs"`
+}
+
+// loadMatrixConfigs loads the configurations of the matrix run from the JSON file.
+func loadMatrixConfigs(path string) ([]matrixConfig, error) {
+	b, err := os.ReadFile(filepath.Clean(path))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	var f matrixFile
+	if err := json.Unmarshal(b, &f); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	if len(f.Configs) == 0 {
+		return nil, fmt.Errorf("%w: %s has no configs", errInvalidOption, path)
+	}
+
+	names := map[string]bool{}
+	for _, c := range f.Configs {
+		if c.Name == "" {
+			return nil, fmt.Errorf("%w: config without name in %s", errInvalidOption, path)
+		}
+		if names[c.Name] {
+			return nil, fmt.Errorf("%w: duplicate config name '%s' in %s", errInvalidOption, c.Name, path)
+		}
+		names[c.Name] = true
+	}
+	return f.Configs, nil
+}
+
+// args returns the 'go test' arguments of the configuration.
+// The flags of the configuration are placed before the arguments. If the arguments have
+// the -tags flag, the tags of the configuration are merged into it because 'go test' uses
+// only the last -tags flag.
+func (c matrixConfig) args(args []string) []string {
+	a := append([]string{}, c.Flags...)
+	if c.Tags == "" {
+		return append(a, args...)
+	}
+	args = append([]string{}, args...)
+	for i, arg := range args {
+		if arg == "-args" || arg == "--args" {
+			break
+		}
+		name, value, hasValue := strings.Cut(strings.TrimLeft(arg, "-"), "=")
+		if !strings.HasPrefix(arg, "-") || name != "tags" {
+			continue
+		}
+		if hasValue {
+			args[i] = "-tags=" + joinTags(c.Tags, value)
+			return append(a, args...)
+		}
+		if i+1 < len(args) {
+			args[i+1] = joinTags(c.Tags, args[i+1])
+			return append(a, args...)
+		}
+	}
+	return append(append(a, "-tags="+c.Tags), args...)
+}
+
+// joinTags joins the comma-separated build tags lists, e.g. "integration" and "linux" to "integration,linux".
+func joinTags(tags ...string) string {
+	var nonEmpty []string
+	for _, t := range tags {
+		if t != "" {
+			nonEmpty = append(nonEmpty, t)
+		}
+	}
+	return strings.Join(nonEmpty, ",")
+}
+
+// environ returns the environment variables added to the current environment.
+func (c matrixConfig) environ() []string {
+	env := environ(c.Env)
+	if c.GoFlags != "" {
+		env = append(env, "GOFLAGS="+c.GoFlags)
+	}
+	return env
+}
+
+// environ returns the environment variables in the format "KEY=value".
+// The variables are sorted by name so that the command is deterministic.
+func environ(vars map[string]string) []string {
+	env := make([]string, 0, len(vars)+1)
+	for k, v := range vars {
+		env = append(env, k+"="+v)
+	}
+	sort.Strings(env)
+	return env
+}
+
+// runMatrix runs the test commands under each configuration in order.
+// The results of each configuration are labeled with the configuration name.
+// Even if a configuration fails, the remaining configurations are run. It returns the first error.
+func (h *hottest) runMatrix(invocations [][]string) error {
+	h.interval.Start()
+	defer h.interval.End()
+
+	var firstErr error
+	for _, c := range h.matrix {
+		fmt.Fprintf(os.Stdout, "[%s] ", c.Name)
+		sub := h.newChild(c.environ())
+		for _, args := range invocations {
+			if err := sub.runTest(c.args(args)); err != nil && firstErr == nil {
+				firstErr = err
+			}
+		}
+		fmt.Fprintln(os.Stdout)
+		h.Merge(c.Name, sub.Aggregator)
+		if h.interrupt.interrupted() {
+			break
+		}
+	}
+	return firstErr
+}
+
+// matrixRow is a test in the matrix table with its result under each configuration.
+type matrixRow struct {
+	pkg     string
+	name    string
+	actions []string
+}
+
+// matrixRows returns the tests that failed under at least one configuration.
+// The results are sorted by the package and the test name.
+func matrixRows(r *testjson.Results, configs []matrixConfig) []matrixRow {
+	index := map[string]int{}
+	for i, c := range configs {
+		index[c.Name] = i
+	}
+
+	rows := map[string]*matrixRow{}
+	for _, t := range r.Tests(testjson.ActionFail) {
+		key := t.Package + " " + t.Name
+		if _, ok := rows[key]; !ok {
+			rows[key] = &matrixRow{pkg: t.Package, name: t.Name}
+		}
+	}
+	for _, t := range r.Tests("") {
+		row, ok := rows[t.Package+" "+t.Name]
+		if !ok {
+			continue
+		}
+		if row.actions == nil {
+			row.actions = make([]string, len(configs))
+			for i := range row.actions {
+				row.actions[i] = matrixNotRun
+			}
+		}
+		if i, ok := index[t.Source]; ok && t.Action != "" {
+			row.actions[i] = t.Action
+		}
+	}
+
+	sorted := make([]matrixRow, 0, len(rows))
+	for _, row := range rows {
+		sorted = append(sorted, *row)
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		if sorted[i].pkg != sorted[j].pkg {
+			return sorted[i].pkg < sorted[j].pkg
+		}
+		return sorted[i].name < sorted[j].name
+	})
+	return sorted
+}
+
+// printMatrix prints the table of the failed tests and their results under each configuration.
+func printMatrix(w io.Writer, r *testjson.Results, configs []matrixConfig) {
+	rows := matrixRows(r, configs)
+	if len(rows) == 0 {
+		return
+	}
+
+	width := len("TEST")
+	for _, row := range rows {
+		if len(row.name) > width {
+			width = len(row.name)
+		}
+	}
+	fmt.Fprintf(w, "[Matrix]\n")
+	fmt.Fprintf(w, " %-*s", width, "TEST")
+	for _, c := range configs {
+		fmt.Fprintf(w, "  %-*s", matrixCellWidth(c), c.Name)
+	}
+	fmt.Fprintf(w, "  PACKAGE\n")
+	for _, row := range rows {
+		fmt.Fprintf(w, " %-*s", width, row.name)
+		for i, c := range configs {
+			fmt.Fprintf(w, "  %s", matrixCell(row.actions[i], matrixCellWidth(c)))
+		}
+		fmt.Fprintf(w, "  %s\n", row.pkg)
+	}
+}
+
+// matrixCellWidth returns the width of the column of the configuration.
+func matrixCellWidth(c matrixConfig) int {
+	if len(c.Name) > len(testjson.ActionFail) {
+		return len(c.Name)
+	}
+	return len(testjson.ActionFail)
+}
+
+// matrixCell returns the colored action padded to the width, e.g. red "FAIL".
+func matrixCell(action string, width int) string {
+	cell := fmt.Sprintf("%-*s", width, strings.ToUpper(action))
+	switch action {
+	case testjson.ActionPass:
+		return color.GreenString(cell)
+	case testjson.ActionFail:
+		return color.RedString(cell)
+	case testjson.ActionSkip:
+		return color.BlueString(cell)
+	}
+	return cell
+}
@@ -0,0 +1,109 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/nao1215/hottest/testjson"
+)
+
+func Test_loadMatrixConfigs(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    []matrixConfig
+		wantErr bool
+	}{
+		{
+			name: "Load configurations",
+			content: `{"configs": [
+				{"name": "default"},
+				{"name": "race", "flags": ["-race"]},
+				{"name": "integration", "tags": "integration", "env": {"CGO_ENABLED": "0"}, "goflags": "-mod=mod"}
+			]}`,
+			want: []matrixConfig{
+				{Name: "default"},
+				{Name: "race", Flags: []string{"-race"}},
+				{Name: "integration", Tags: "integration", Env: map[string]string{"CGO_ENABLED": "0"}, GoFlags: "-mod=mod"},
+			},
+		},
+		{name: "No configurations", content: `{"configs": []}`, wantErr: true},
+		{name: "Configuration without name", content: `{"configs": [{"flags": ["-race"]}]}`, wantErr: true},
+		{name: "Duplicate names", content: `{"configs": [{"name": "a"}, {"name": "a"}]}`, wantErr: true},
+		{name: "Invalid JSON", content: `configs`, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "matrix.json")
+			if err := os.WriteFile(path, []byte(tt.content), 0o600); err != nil {
+				t.Fatal(err)
+			}
+			got, err := loadMatrixConfigs(path)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("loadMatrixConfigs() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if diff := cmp.Diff(tt.want, got); !tt.wantErr && diff != "" {
+				t.Errorf("loadMatrixConfigs() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func Test_matrixConfig(t *testing.T) {
+	c := matrixConfig{
+		Flags:   []string{"-race"},
+		Tags:    "integration",
+		Env:     map[string]string{"GOARCH": "386", "CGO_ENABLED": "0"},
+		GoFlags: "-mod=mod",
+	}
+	if diff := cmp.Diff([]string{"-race", "-tags=integration", "-cover", "./..."}, c.args([]string{"-cover", "./..."})); diff != "" {
+		t.Errorf("args() mismatch (-want +got):\n%s", diff)
+	}
+	for _, tt := range []struct {
+		args []string
+		want []string
+	}{
+		{[]string{"-tags=linux", "./..."}, []string{"-race", "-tags=integration,linux", "./..."}},
+		{[]string{"-tags", "linux", "./..."}, []string{"-race", "-tags", "integration,linux", "./..."}},
+		{[]string{"--tags=", "./..."}, []string{"-race", "-tags=integration", "./..."}},
+		{[]string{"./...", "-args", "-tags=linux"}, []string{"-race", "-tags=integration", "./...", "-args", "-tags=linux"}},
+	} {
+		if diff := cmp.Diff(tt.want, c.args(tt.args)); diff != "" {
+			t.Errorf("args(%v) mismatch (-want +got):\n%s", tt.args, diff)
+		}
+	}
+	if diff := cmp.Diff([]string{"CGO_ENABLED=0", "GOARCH=386", "GOFLAGS=-mod=mod"}, c.environ()); diff != "" {
+		t.Errorf("environ() mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func Test_matrixRows(t *testing.T) {
+	linux := newHottestFromLog(t, "testdata/sample.json")
+	windows := newHottestFromLog(t, "testdata/shard1.json")
+	darwin := newHottestFromLog(t, "testdata/shard2.json")
+	h := newHottestForLog()
+	h.matrix = []matrixConfig{{Name: "linux"}, {Name: "windows"}, {Name: "darwin"}}
+	h.Merge("linux", linux.Aggregator)
+	h.Merge("windows", windows.Aggregator)
+	h.Merge("darwin", darwin.Aggregator)
+
+	got := matrixRows(h.Results, h.matrix)
+	want := []matrixRow{
+		{pkg: "example.com/sample/a", name: "TestFail", actions: []string{testjson.ActionFail, testjson.ActionFail, matrixNotRun}},
+		{pkg: "example.com/sample/a", name: "TestFail/sub_ng", actions: []string{testjson.ActionFail, testjson.ActionFail, matrixNotRun}},
+	}
+	if diff := cmp.Diff(want, got, cmp.AllowUnexported(matrixRow{})); diff != "" {
+		t.Errorf("matrixRows() mismatch (-want +got):\n%s", diff)
+	}
+
+	var b strings.Builder
+	if err := h.writeMarkdown(&b); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(b.String(), "### Matrix") {
+		t.Errorf("markdown does not contain the matrix table\n%s", b.String())
+	}
+}
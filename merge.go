@@ -0,0 +1,86 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// mergeSource is a 'go test -json' log file and its label.
+type mergeSource struct {
+	label string
+	path  string
+}
+
+// parseMergeSource parses the argument in the format "[label=]path".
+// If the label is omitted, the file name without the extension is used.
+// The part before "=" is not a label if it contains a path separator (e.g. "out/run=1.json")
+// or if the whole argument is an existing file.
+func parseMergeSource(arg string) mergeSource {
+	if label, path, ok := strings.Cut(arg, "="); ok && label != "" && !strings.ContainsAny(label, `/\`) {
+		if _, err := os.Stat(arg); err != nil {
+			return mergeSource{label: label, path: path}
+		}
+	}
+	return mergeSource{
+		label: strings.TrimSuffix(filepath.Base(arg), filepath.Ext(arg)),
+		path:  arg,
+	}
+}
+
+// mergeLogs reads the 'go test -json' log files and combines them into a hottest.
+// Each result is labeled with its source. The error messages are extracted per log
+// because the output of the logs must not be interleaved.
+func mergeLogs(sources []mergeSource) (*hottest, error) {
+	h := newHottestForLog()
+	for _, src := range sources {
+		sub, err := readLog(src.path)
+		if err != nil {
+			return nil, err
+		}
+		h.Merge(src.label, sub.Aggregator)
+	}
+	h.interval.Started = h.Results.Started
+	h.interval.Finished = h.Results.Finished
+	return h, nil
+}
+
+// runMerge runs the 'hottest merge' subcommand.
+func runMerge(args []string) error {
+	fs := flag.NewFlagSet("merge", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	md := fs.String("markdown", "", "write the markdown report of the merged results to the file")
+	if err := fs.Parse(args); err != nil {
+		return fmt.Errorf("%w: %s", errInvalidOption, err.Error())
+	}
+	if fs.NArg() == 0 {
+		return fmt.Errorf("%w: 'hottest merge' requires 'go test -json' log files", errInvalidOption)
+	}
+
+	sources := make([]mergeSource, 0, fs.NArg())
+	labels := map[string]string{}
+	for _, arg := range fs.Args() {
+		src := parseMergeSource(arg)
+		// The results of the same label would overwrite each other.
+		if path, ok := labels[src.label]; ok {
+			return fmt.Errorf("%w: %s and %s have the same label '%s': specify the labels in the format label=file",
+				errInvalidOption, path, src.path, src.label)
+		}
+		labels[src.label] = src.path
+		sources = append(sources, src)
+	}
+	h, err := mergeLogs(sources)
+	if err != nil {
+		return err
+	}
+	h.testResult()
+	if *md != "" {
+		if err := h.writeMarkdownFile(*md); err != nil {
+			return err
+		}
+	}
+	return h.testError(nil)
+}
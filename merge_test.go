@@ -0,0 +1,150 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/nao1215/hottest/testjson"
+)
+
+func Test_parseMergeSource(t *testing.T) {
+	tests := []struct {
+		name string
+		arg  string
+		want mergeSource
+	}{
+		{name: "label and path", arg: "linux=logs/linux.json", want: mergeSource{label: "linux", path: "logs/linux.json"}},
+		{name: "path only", arg: "logs/shard1.json", want: mergeSource{label: "shard1", path: "logs/shard1.json"}},
+		{name: "empty label", arg: "=shard1.json", want: mergeSource{label: "=shard1", path: "=shard1.json"}},
+		{name: "path with = in the directory", arg: "out/run=1.json", want: mergeSource{label: "run=1", path: "out/run=1.json"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := parseMergeSource(tt.arg)
+			if diff := cmp.Diff(tt.want, got, cmp.AllowUnexported(mergeSource{})); diff != "" {
+				t.Errorf("parseMergeSource() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func Test_parseMergeSource_existingFile(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "run=1.json"), []byte{}, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Chdir(wd) //nolint
+
+	got := parseMergeSource("run=1.json")
+	if diff := cmp.Diff(mergeSource{label: "run=1", path: "run=1.json"}, got, cmp.AllowUnexported(mergeSource{})); diff != "" {
+		t.Errorf("parseMergeSource() mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func Test_mergeLogs(t *testing.T) {
+	t.Run("Merge the logs of the shards into one result", func(t *testing.T) {
+		h, err := mergeLogs([]mergeSource{
+			{label: "shard1", path: "testdata/shard1.json"},
+			{label: "shard2", path: "testdata/shard2.json"},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if diff := cmp.Diff(testjson.TestStats{Pass: 3, Fail: 2, Skip: 1, Total: 6}, h.Stats); diff != "" {
+			t.Errorf("stats mismatch (-want +got):\n%s", diff)
+		}
+		if len(h.Results.Packages) != 2 {
+			t.Errorf("got %d packages, want 2", len(h.Results.Packages))
+		}
+		if h.interval.Duration() <= 0 {
+			t.Errorf("duration = %s, want positive duration", h.interval.Duration())
+		}
+		for _, p := range h.Results.Packages {
+			if p.Source == "" {
+				t.Errorf("package %s has no source", p.Name)
+			}
+		}
+	})
+
+	t.Run("Label the results of the same package in different logs", func(t *testing.T) {
+		h, err := mergeLogs([]mergeSource{
+			{label: "linux", path: "testdata/sample.json"},
+			{label: "windows", path: "testdata/sample.json"},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if diff := cmp.Diff(testjson.TestStats{Pass: 6, Fail: 4, Skip: 2, Total: 12}, h.Stats); diff != "" {
+			t.Errorf("stats mismatch (-want +got):\n%s", diff)
+		}
+		if len(h.Results.Packages) != 4 {
+			t.Errorf("got %d packages, want 4", len(h.Results.Packages))
+		}
+
+		failures := []string{}
+		for _, f := range h.Results.Failures() {
+			failures = append(failures, f.Label()+f.Name)
+		}
+		want := []string{"[linux] TestFail/sub_ng", "[windows] TestFail/sub_ng"}
+		if diff := cmp.Diff(want, failures); diff != "" {
+			t.Errorf("failures mismatch (-want +got):\n%s", diff)
+		}
+
+		labeled := 0
+		for _, msg := range h.FailMessages() {
+			if strings.HasPrefix(msg, "[linux] --- FAIL") || strings.HasPrefix(msg, "[windows] --- FAIL") {
+				labeled++
+			}
+		}
+		if labeled == 0 {
+			t.Errorf("error messages are not labeled: %v", h.FailMessages())
+		}
+
+		var b bytes.Buffer
+		if err := h.writeMarkdown(&b); err != nil {
+			t.Fatal(err)
+		}
+		for _, s := range []string{"SOURCE", "| linux", "| windows", "[windows] `"} {
+			if !strings.Contains(b.String(), s) {
+				t.Errorf("markdown report does not contain %q:\n%s", s, b.String())
+			}
+		}
+	})
+
+	t.Run("If a log file does not exist, return error", func(t *testing.T) {
+		if _, err := mergeLogs([]mergeSource{
+			{label: "shard1", path: "testdata/shard1.json"},
+			{label: "not_exist", path: "testdata/not_exist.json"},
+		}); err == nil {
+			t.Error("mergeLogs() should return error")
+		}
+	})
+}
+
+func Test_runMerge(t *testing.T) {
+	t.Run("If the labels are the same, return error", func(t *testing.T) {
+		err := runMerge([]string{"a=testdata/shard1.json", "a=testdata/shard2.json"})
+		if !errors.Is(err, errInvalidOption) {
+			t.Errorf("runMerge() error = %v, want %v", err, errInvalidOption)
+		}
+	})
+
+	t.Run("If the file names are the same, return error", func(t *testing.T) {
+		err := runMerge([]string{"testdata/shard1.json", "testdata/../testdata/shard1.json"})
+		if !errors.Is(err, errInvalidOption) {
+			t.Errorf("runMerge() error = %v, want %v", err, errInvalidOption)
+		}
+	})
+}
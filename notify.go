@@ -0,0 +1,192 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/nao1215/hottest/testjson"
+)
+
+const (
+	// notifyFormatJSON posts the summary as it is.
+	notifyFormatJSON = "json"
+	// notifyFormatSlack posts a message for Slack incoming webhooks.
+	notifyFormatSlack = "slack"
+	// notifyFormatTeams posts a message card for Microsoft Teams incoming webhooks.
+	notifyFormatTeams = "teams"
+
+	// notifyTimeout is the timeout of the webhook request.
+	notifyTimeout = 10 * time.Second
+	// notifyFailuresNum is the maximum number of the failed tests listed in the message.
+	notifyFailuresNum = 10
+	// notifyStatusInterrupted is the status of the run that is interrupted by a signal.
+	notifyStatusInterrupted = "interrupted"
+	// notifyStatusError is the status of the run whose tests could not finish, e.g. a build failure or a timeout.
+	notifyStatusError = "error"
+)
+
+// notifyTemplates is the payload templates of the built-in formats.
+var notifyTemplates = map[string]string{
+	notifyFormatSlack: `{"text": {{json .Text}}}`,
+	notifyFormatTeams: `{"@type": "MessageCard", "@context": "https://schema.org/extensions", ` +
+		`"themeColor": "{{if eq .Status "pass"}}1a7f37{{else}}cf222e{{end}}", ` +
+		`"summary": {{json .Title}}, "title": {{json .Title}}, "text": {{json .Text}}}`,
+}
+
+// webhookPayload is the summary of the test run that is posted to the webhook.
+// It is also the data of the payload templates.
+type webhookPayload struct {
+	// Status is "pass", "fail", "error" or "interrupted".
+	Status string `json:"status"`
+	// Outcome is the outcome of the run that tells the reason of the error status, e.g. "build_failure".
+	Outcome string `json:"outcome"`
+	// Title is the one-line summary, e.g. "hottest: FAIL 3/2/1 (ok/ng/skip) in 1.2s".
+	Title string `json:"title"`
+	// Text is the title followed by the failed tests.
+	Text     string             `json:"text"`
+	Stats    testjson.TestStats `json:"stats"`
+	Started  time.Time          `json:"started"`
+	Elapsed  float64            `json:"elapsed"`
+	Commit   string             `json:"commit"`
+	Branch   string             `json:"branch"`
+	Failures []reporterTest     `json:"failures"`
+	// Benchmarks is the benchmark results.
+	Benchmarks []*testjson.Benchmark `json:"benchmarks"`
+}
+
+// newWebhookPayload returns the payload of the summary.
+func newWebhookPayload(s Summary, commit, branch string) webhookPayload {
+	p := webhookPayload{
+		Status:     notifyStatus(s.Outcome),
+		Outcome:    s.Outcome,
+		Stats:      s.Stats,
+		Started:    s.Started,
+		Elapsed:    s.Elapsed,
+		Commit:     commit,
+		Branch:     branch,
+		Failures:   s.Failures,
+		Benchmarks: s.Benchmarks,
+	}
+	status := strings.ToUpper(p.Status)
+	if p.Status == notifyStatusError && s.Outcome != outcomeError {
+		status += " (" + strings.ReplaceAll(s.Outcome, "_", " ") + ")"
+	}
+	p.Title = fmt.Sprintf("hottest: %s %d/%d/%d (ok/ng/skip) in %s",
+		status, s.Stats.Pass, s.Stats.Fail, s.Stats.Skip, elapsedString(s.Elapsed))
+	if branch != "" && len(commit) >= 7 {
+		p.Title += fmt.Sprintf(" on %s@%s", branch, commit[:7])
+	}
+
+	lines := []string{p.Title}
+	for i, f := range s.Failures {
+		if i == notifyFailuresNum {
+			lines = append(lines, fmt.Sprintf("... and %d more", len(s.Failures)-notifyFailuresNum))
+			break
+		}
+		lines = append(lines, fmt.Sprintf("- %s %s (%s)", f.Package, f.Name, failureSummary(f.Messages)))
+	}
+	p.Text = strings.Join(lines, "\n")
+	return p
+}
+
+// notifyStatus returns the status of the outcome of the run. The build failures and the timeouts
+// are reported as "error" because the stats do not count them as failed tests.
+func notifyStatus(outcome string) string {
+	switch outcome {
+	case outcomePass:
+		return testjson.ActionPass
+	case outcomeFail:
+		return testjson.ActionFail
+	case outcomeInterrupted:
+		return notifyStatusInterrupted
+	default:
+		return notifyStatusError
+	}
+}
+
+// webhookReporter is a reporter that posts the summary to the webhook when the run ends.
+// The failure of the request is printed to stderr and does not change the result of hottest.
+type webhookReporter struct {
+	url    string
+	tmpl   *template.Template
+	client *http.Client
+}
+
+// newWebhookReporter returns a webhookReporter. If path is not empty, the payload is
+// rendered by the template in the file. Otherwise the built-in template of the format is used.
+func newWebhookReporter(url, format, path string) (*webhookReporter, error) {
+	text, ok := notifyTemplates[format]
+	if path != "" {
+		b, err := os.ReadFile(filepath.Clean(path))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", path, err)
+		}
+		text, ok = string(b), true
+	}
+
+	r := &webhookReporter{url: url, client: &http.Client{Timeout: notifyTimeout}}
+	if !ok {
+		return r, nil
+	}
+	tmpl, err := template.New("payload").Funcs(template.FuncMap{
+		"json": func(v interface{}) (string, error) {
+			b, err := json.Marshal(v)
+			return string(b), err
+		},
+	}).Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid payload template: %s", errInvalidOption, err.Error())
+	}
+	r.tmpl = tmpl
+	return r, nil
+}
+
+// OnStart does nothing.
+func (w *webhookReporter) OnStart() {}
+
+// OnEvent does nothing.
+func (w *webhookReporter) OnEvent(testjson.TestOutputJSON) {}
+
+// OnTestFinished does nothing.
+func (w *webhookReporter) OnTestFinished(*testjson.TestResult) {}
+
+// OnFinish posts the summary to the webhook.
+func (w *webhookReporter) OnFinish(s Summary) {
+	p := newWebhookPayload(s, gitOutput("rev-parse", "HEAD"), gitOutput("rev-parse", "--abbrev-ref", "HEAD"))
+	if err := w.post(p); err != nil {
+		fmt.Fprintf(os.Stderr, "failed to notify the webhook: %s\n", err.Error())
+	}
+}
+
+// post posts the payload to the webhook.
+func (w *webhookReporter) post(p webhookPayload) error {
+	var body bytes.Buffer
+	if w.tmpl == nil {
+		if err := json.NewEncoder(&body).Encode(p); err != nil {
+			return err
+		}
+	} else if err := w.tmpl.Execute(&body, p); err != nil {
+		return err
+	}
+
+	resp, err := w.client.Post(w.url, "application/json", &body) //#nosec G107 -- the URL is specified by the user
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close() //nolint
+	if _, err := io.Copy(io.Discard, resp.Body); err != nil {
+		return err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("unexpected status: %s", resp.Status)
+	}
+	return nil
+}
@@ -0,0 +1,199 @@
+package main
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/nao1215/hottest/testjson"
+)
+
+// newWebhookServer returns a server that records the request bodies and responds with the status.
+func newWebhookServer(t *testing.T, status int) (*httptest.Server, *[]string) {
+	t.Helper()
+
+	bodies := []string{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.Header.Get("Content-Type") != "application/json" {
+			t.Errorf("unexpected request: %s %s", r.Method, r.Header.Get("Content-Type"))
+		}
+		b, err := io.ReadAll(r.Body)
+		if err != nil {
+			t.Error(err)
+		}
+		bodies = append(bodies, string(b))
+		w.WriteHeader(status)
+	}))
+	t.Cleanup(srv.Close)
+	return srv, &bodies
+}
+
+func Test_webhookReporter(t *testing.T) {
+	summary := Summary{
+		Stats:   testjson.TestStats{Pass: 3, Fail: 1, Skip: 1, Total: 5},
+		Elapsed: 1.5,
+		Outcome: outcomeFail,
+		Failures: []reporterTest{
+			{Package: "example.com/a", Name: "TestFail", Action: testjson.ActionFail, Messages: []string{"    a_test.go:10: got 1, want 2"}},
+		},
+	}
+
+	t.Run("Post the summary as JSON", func(t *testing.T) {
+		srv, bodies := newWebhookServer(t, http.StatusOK)
+		r, err := newWebhookReporter(srv.URL, notifyFormatJSON, "")
+		if err != nil {
+			t.Fatal(err)
+		}
+		r.OnFinish(summary)
+
+		if len(*bodies) != 1 {
+			t.Fatalf("got %d requests, want 1", len(*bodies))
+		}
+		var got webhookPayload
+		if err := json.Unmarshal([]byte((*bodies)[0]), &got); err != nil {
+			t.Fatal(err)
+		}
+		if got.Status != testjson.ActionFail || got.Stats != summary.Stats || len(got.Failures) != 1 {
+			t.Errorf("unexpected payload: %+v", got)
+		}
+		if !strings.Contains(got.Text, "- example.com/a TestFail (a_test.go:10: got 1, want 2)") {
+			t.Errorf("text does not contain the failed test: %s", got.Text)
+		}
+	})
+
+	t.Run("Post the error status if the package failed to build", func(t *testing.T) {
+		h := newHottestFromLog(t, "testdata/build.json")
+		srv, bodies := newWebhookServer(t, http.StatusOK)
+		r, err := newWebhookReporter(srv.URL, notifyFormatJSON, "")
+		if err != nil {
+			t.Fatal(err)
+		}
+		r.OnFinish(h.summary(h.testError(errExitStatus)))
+
+		if len(*bodies) != 1 {
+			t.Fatalf("got %d requests, want 1", len(*bodies))
+		}
+		var got webhookPayload
+		if err := json.Unmarshal([]byte((*bodies)[0]), &got); err != nil {
+			t.Fatal(err)
+		}
+		if got.Status != notifyStatusError || got.Outcome != outcomeBuildFailure {
+			t.Errorf("Status = %q, Outcome = %q, want %q and %q", got.Status, got.Outcome, notifyStatusError, outcomeBuildFailure)
+		}
+		if !strings.HasPrefix(got.Title, "hottest: ERROR (build failure) 0/0/0 (ok/ng/skip)") {
+			t.Errorf("Title = %q, want the build failure", got.Title)
+		}
+	})
+
+	t.Run("Post the benchmark results", func(t *testing.T) {
+		h := newHottestFromLog(t, "testdata/bench.json")
+		srv, bodies := newWebhookServer(t, http.StatusOK)
+		r, err := newWebhookReporter(srv.URL, notifyFormatJSON, "")
+		if err != nil {
+			t.Fatal(err)
+		}
+		r.OnFinish(h.summary(nil))
+
+		if len(*bodies) != 1 {
+			t.Fatalf("got %d requests, want 1", len(*bodies))
+		}
+		var got webhookPayload
+		if err := json.Unmarshal([]byte((*bodies)[0]), &got); err != nil {
+			t.Fatal(err)
+		}
+		if diff := cmp.Diff(h.Results.Benchmarks(), got.Benchmarks); diff != "" {
+			t.Errorf("Benchmarks mismatch (-want +got):\n%s", diff)
+		}
+	})
+
+	t.Run("Post the Slack and Teams messages", func(t *testing.T) {
+		for _, format := range []string{notifyFormatSlack, notifyFormatTeams} {
+			srv, bodies := newWebhookServer(t, http.StatusOK)
+			r, err := newWebhookReporter(srv.URL, format, "")
+			if err != nil {
+				t.Fatal(err)
+			}
+			r.OnFinish(summary)
+
+			var got map[string]interface{}
+			if err := json.Unmarshal([]byte((*bodies)[0]), &got); err != nil {
+				t.Fatalf("%s payload is not a valid JSON: %v\n%s", format, err, (*bodies)[0])
+			}
+			if !strings.HasPrefix(got["text"].(string), "hottest: FAIL 3/1/1 (ok/ng/skip) in 1.5s") {
+				t.Errorf("%s text = %q", format, got["text"])
+			}
+		}
+	})
+
+	t.Run("Post the payload rendered by the custom template", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "payload.tmpl")
+		if err := os.WriteFile(path, []byte(`{"content": {{json .Title}}, "failed": {{.Stats.Fail}}}`), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		srv, bodies := newWebhookServer(t, http.StatusOK)
+		r, err := newWebhookReporter(srv.URL, notifyFormatJSON, path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := r.post(newWebhookPayload(summary, "0123456789", "main")); err != nil {
+			t.Fatal(err)
+		}
+		want := `{"content": "hottest: FAIL 3/1/1 (ok/ng/skip) in 1.5s on main@0123456", "failed": 1}`
+		if diff := cmp.Diff(want, (*bodies)[0]); diff != "" {
+			t.Errorf("payload mismatch (-want +got):\n%s", diff)
+		}
+	})
+
+	t.Run("If the template is invalid, return error", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "payload.tmpl")
+		if err := os.WriteFile(path, []byte(`{{.Title`), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := newWebhookReporter("http://localhost", notifyFormatJSON, path); err == nil {
+			t.Error("newWebhookReporter() should return error")
+		}
+	})
+
+	t.Run("If the webhook responds with an error, return error without panic", func(t *testing.T) {
+		srv, _ := newWebhookServer(t, http.StatusInternalServerError)
+		r, err := newWebhookReporter(srv.URL, notifyFormatSlack, "")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := r.post(newWebhookPayload(summary, "", "")); err == nil {
+			t.Error("post() should return error")
+		}
+		r.OnFinish(summary)
+	})
+}
+
+func Test_newWebhookPayload(t *testing.T) {
+	t.Run("The status of the interrupted run is interrupted even if tests failed", func(t *testing.T) {
+		got := newWebhookPayload(Summary{Stats: testjson.TestStats{Pass: 1, Fail: 1, Total: 2}, Interrupted: true, Outcome: outcomeInterrupted}, "", "")
+		if got.Status != notifyStatusInterrupted {
+			t.Errorf("Status = %q, want %q", got.Status, notifyStatusInterrupted)
+		}
+		if !strings.HasPrefix(got.Title, "hottest: INTERRUPTED 1/1/0") {
+			t.Errorf("Title = %q, want the interrupted status", got.Title)
+		}
+	})
+}
+
+func Test_run_notifyWebhook(t *testing.T) {
+	t.Run("The notification failure does not change the exit code", func(t *testing.T) {
+		srv, bodies := newWebhookServer(t, http.StatusInternalServerError)
+		err := run([]string{"hottest", "-hottest.notify-webhook=" + srv.URL, "./testjson"})
+		if err != nil {
+			t.Errorf("run() error = %v, want nil", err)
+		}
+		if len(*bodies) != 1 {
+			t.Errorf("got %d requests, want 1", len(*bodies))
+		}
+	})
+}
@@ -0,0 +1,275 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+)
+
+const (
+	// optionPrefix is the prefix of the hottest options. The arguments without
+	// this prefix are passed to 'go test' as they are.
+	optionPrefix = "hottest."
+
+	// formatDots prints a colored dot for each test.
+	formatDots = "dots"
+	// formatTeamCity prints TeamCity service messages for each test.
+	formatTeamCity = "teamcity"
+)
+
+// errInvalidOption is an error that occurs when the hottest option is invalid.
+var errInvalidOption = errors.New("invalid option")
+
+// options holds the hottest options.
+type options struct {
+	// format is the output format of the test progress.
+	format string
+	// html is the file path of the HTML report. If it is empty, the HTML report is not generated.
+	html string
+	// history is the directory where the test results are saved. If it is empty, the test results are not saved.
+	history string
+	// changed is the git ref to compare with. If it is not empty, only the packages
+	// affected by the changes from the ref are tested.
+	changed string
+	// shard is the shard that this run executes in the format "i/n". If it is empty, all tests are run.
+	shard string
+	// shardBy is the unit of the sharding: "package" or "test".
+	shardBy string
+	// shardTiming is the 'go test -json' log of a previous run that is used to balance the shards.
+	shardTiming string
+	// log is the file path where the 'go test -json' log is saved. If it is empty, the log is not saved.
+	log string
+	// matrix is the JSON file of the configurations that the tests are run under.
+	// If it is empty, the tests are run once.
+	matrix string
+	// parallel is the maximum number of 'go test' commands that run concurrently.
+	parallel int
+	// groups is the JSON file of the package groups that are tested by separate 'go test' commands.
+	groups string
+	// reporters is the commands of the external reporters that receive the events as JSON lines on stdin.
+	reporters []string
+	// benchBaseline is the file of the benchmark results to compare with. If it does not exist,
+	// the results of the run are saved to it.
+	benchBaseline string
+	// benchThreshold is the rate of the benchmark regression that fails the run, e.g. 0.1 for 10%.
+	// If it is 0, the regressions are reported but do not fail the run.
+	benchThreshold float64
+	// groupSkips groups the skipped tests by reason in the terminal and the markdown reports.
+	groupSkips bool
+	// maxSkip is the maximum number of skipped tests. If it is negative, the number is not limited.
+	maxSkip int
+	// failOnSkipPattern is the regular expression of the skip reasons that fail the run.
+	// If it is empty, no skip reason fails the run.
+	failOnSkipPattern string
+	// requireTests fails the run if no tests ran.
+	requireTests bool
+	// notifyWebhook is the URL of the webhook that receives the summary. If it is empty, no notification is sent.
+	notifyWebhook string
+	// notifyFormat is the payload format of the webhook: "json", "slack" or "teams".
+	notifyFormat string
+	// notifyTemplate is the file of the payload template. It overrides notifyFormat.
+	notifyTemplate string
+}
+
+// newFlagSet returns the flag set of the hottest options that are stored in opts.
+func newFlagSet(opts *options) *flag.FlagSet {
+	fs := flag.NewFlagSet("hottest", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	fs.StringVar(&opts.format, optionPrefix+"format", "",
+		fmt.Sprintf("output format of the test progress: %s or %s", formatDots, formatTeamCity))
+	fs.StringVar(&opts.html, optionPrefix+"html", "", "write the HTML report to the file")
+	fs.Var(&optionalValue{target: &opts.history, defaultValue: defaultHistoryDir}, optionPrefix+"history",
+		fmt.Sprintf("save the test results to the history directory (default %s)", defaultHistoryDir))
+	fs.Var(&optionalValue{target: &opts.changed, defaultValue: defaultBaseRef}, optionPrefix+"changed",
+		fmt.Sprintf("test only the packages affected by the changes from the git ref (default %s)", defaultBaseRef))
+	fs.StringVar(&opts.shard, optionPrefix+"shard", "", "run only the i-th of n shards in the format i/n (e.g. 1/3)")
+	fs.StringVar(&opts.shardBy, optionPrefix+"shard-by", shardByPackage,
+		fmt.Sprintf("unit of the sharding: %s or %s", shardByPackage, shardByTest))
+	fs.StringVar(&opts.shardTiming, optionPrefix+"shard-timing", "",
+		"'go test -json' log of a previous run that is used to balance the shards")
+	fs.StringVar(&opts.log, optionPrefix+"log", "", "save the 'go test -json' log to the file")
+	fs.StringVar(&opts.matrix, optionPrefix+"matrix", "",
+		"run the tests under each configuration (flags, tags, env, GOFLAGS) in the JSON file")
+	fs.IntVar(&opts.parallel, optionPrefix+"parallel", 1,
+		"maximum number of 'go test' commands that run concurrently; without groups, the packages are split into the number of groups")
+	fs.StringVar(&opts.groups, optionPrefix+"groups", "",
+		"test each package group (packages, flags, env) in the JSON file by a separate 'go test' command")
+	fs.Var((*stringsValue)(&opts.reporters), optionPrefix+"reporter",
+		"run the command that receives the test events as JSON lines on stdin (can be specified multiple times)")
+	fs.StringVar(&opts.benchBaseline, optionPrefix+"bench-baseline", "",
+		"save the benchmark results to the file, or compare with them if the file exists")
+	fs.Float64Var(&opts.benchThreshold, optionPrefix+"bench-threshold", 0,
+		"fail if a benchmark is significantly worse than the baseline by more than the rate (e.g. 0.1 for 10%)")
+	fs.BoolVar(&opts.groupSkips, optionPrefix+"group-skips", false, "group the skipped tests by reason")
+	fs.IntVar(&opts.maxSkip, optionPrefix+"max-skip", -1, "fail if more tests than the number are skipped (-1 means no limit)")
+	fs.StringVar(&opts.failOnSkipPattern, optionPrefix+"fail-on-skip-pattern", "",
+		"fail if the reason of a skipped test matches the regular expression")
+	fs.BoolVar(&opts.requireTests, optionPrefix+"require-tests", false, "fail if no tests ran")
+	fs.StringVar(&opts.notifyWebhook, optionPrefix+"notify-webhook", "", "post the summary to the webhook URL when the run ends")
+	fs.StringVar(&opts.notifyFormat, optionPrefix+"notify-format", notifyFormatJSON,
+		fmt.Sprintf("payload format of the webhook: %s, %s or %s", notifyFormatJSON, notifyFormatSlack, notifyFormatTeams))
+	fs.StringVar(&opts.notifyTemplate, optionPrefix+"notify-template", "",
+		"file of the Go text/template that renders the webhook payload")
+	return fs
+}
+
+// parseOptions separates the hottest options from the 'go test' arguments.
+// The hottest options start with "-hottest." (e.g. "-hottest.format=teamcity").
+func parseOptions(args []string) (*options, []string, error) {
+	opts := &options{}
+	fs := newFlagSet(opts)
+
+	hottestArgs := []string{}
+	goTestArgs := []string{}
+	for i := 0; i < len(args); i++ {
+		name := strings.SplitN(strings.TrimLeft(args[i], "-"), "=", 2)[0]
+		if !strings.HasPrefix(args[i], "-") || !strings.HasPrefix(name, optionPrefix) {
+			goTestArgs = append(goTestArgs, args[i])
+			continue
+		}
+		hottestArgs = append(hottestArgs, args[i])
+
+		// The value of the option may be the next argument (e.g. "-hottest.format teamcity").
+		if strings.Contains(args[i], "=") || isBoolFlag(fs.Lookup(name)) || i+1 >= len(args) {
+			continue
+		}
+		i++
+		hottestArgs = append(hottestArgs, args[i])
+	}
+
+	if err := fs.Parse(hottestArgs); err != nil {
+		return nil, nil, fmt.Errorf("%w: %s", errInvalidOption, err.Error())
+	}
+
+	switch opts.format {
+	case "":
+		opts.format = formatDots
+		if _, ok := detectCIProvider().(teamCity); ok {
+			opts.format = formatTeamCity
+		}
+	case formatDots, formatTeamCity:
+	default:
+		return nil, nil, fmt.Errorf("%w: unknown format '%s'", errInvalidOption, opts.format)
+	}
+
+	switch opts.shardBy {
+	case shardByPackage, shardByTest:
+	default:
+		return nil, nil, fmt.Errorf("%w: unknown shard unit '%s'", errInvalidOption, opts.shardBy)
+	}
+	if opts.shard != "" {
+		if _, err := parseShard(opts.shard); err != nil {
+			return nil, nil, err
+		}
+		// The tests of the shard are selected by -run, so the -run of the user would be overridden.
+		if opts.shardBy == shardByTest && hasGoTestFlag(goTestArgs, "run") {
+			return nil, nil, fmt.Errorf("%w: -run cannot be used with -%sshard-by=%s",
+				errInvalidOption, optionPrefix, shardByTest)
+		}
+	}
+
+	switch opts.notifyFormat {
+	case notifyFormatJSON, notifyFormatSlack, notifyFormatTeams:
+	default:
+		return nil, nil, fmt.Errorf("%w: unknown notify format '%s'", errInvalidOption, opts.notifyFormat)
+	}
+
+	if opts.benchThreshold < 0 {
+		return nil, nil, fmt.Errorf("%w: bench threshold must be 0 or more: %g", errInvalidOption, opts.benchThreshold)
+	}
+
+	if opts.maxSkip < -1 {
+		return nil, nil, fmt.Errorf("%w: max skip must be -1 or more: %d", errInvalidOption, opts.maxSkip)
+	}
+	if _, err := regexp.Compile(opts.failOnSkipPattern); err != nil {
+		return nil, nil, fmt.Errorf("%w: invalid skip pattern: %s", errInvalidOption, err.Error())
+	}
+
+	if opts.parallel < 1 {
+		return nil, nil, fmt.Errorf("%w: parallel must be 1 or more: %d", errInvalidOption, opts.parallel)
+	}
+	if opts.groups != "" || opts.parallel > 1 {
+		switch {
+		case opts.shard != "":
+			return nil, nil, fmt.Errorf("%w: -%sparallel and -%sgroups cannot be used with -%sshard",
+				errInvalidOption, optionPrefix, optionPrefix, optionPrefix)
+		case opts.matrix != "":
+			return nil, nil, fmt.Errorf("%w: -%sparallel and -%sgroups cannot be used with -%smatrix",
+				errInvalidOption, optionPrefix, optionPrefix, optionPrefix)
+		case opts.groups != "" && opts.changed != "":
+			return nil, nil, fmt.Errorf("%w: -%sgroups cannot be used with -%schanged",
+				errInvalidOption, optionPrefix, optionPrefix)
+		}
+	}
+	return opts, goTestArgs, nil
+}
+
+// optionalValue is a flag value that can be specified with or without a value,
+// e.g. "-hottest.history" or "-hottest.history=dir". If the value is omitted, defaultValue is set.
+type optionalValue struct {
+	target       *string
+	defaultValue string
+}
+
+// String returns the value.
+func (o *optionalValue) String() string {
+	if o.target == nil {
+		return ""
+	}
+	return *o.target
+}
+
+// Set sets the value. The flag package passes "true" if the value is omitted.
+func (o *optionalValue) Set(s string) error {
+	switch s {
+	case "true":
+		*o.target = o.defaultValue
+	case "false":
+		*o.target = ""
+	default:
+		*o.target = s
+	}
+	return nil
+}
+
+// IsBoolFlag returns true so that the value can be omitted.
+func (o *optionalValue) IsBoolFlag() bool {
+	return true
+}
+
+// stringsValue is a flag value that can be specified multiple times.
+type stringsValue []string
+
+// String returns the values joined by commas.
+func (s *stringsValue) String() string {
+	if s == nil {
+		return ""
+	}
+	return strings.Join(*s, ",")
+}
+
+// Set appends the value.
+func (s *stringsValue) Set(v string) error {
+	*s = append(*s, v)
+	return nil
+}
+
+// isBoolFlag returns true if the flag does not require a value.
+func isBoolFlag(f *flag.Flag) bool {
+	if f == nil {
+		return false
+	}
+	b, ok := f.Value.(interface{ IsBoolFlag() bool })
+	return ok && b.IsBoolFlag()
+}
+
+// optionsUsage prints the usage of the hottest options.
+func optionsUsage() {
+	fmt.Println("Options:")
+	newFlagSet(&options{}).VisitAll(func(f *flag.Flag) {
+		fmt.Printf("  -%s\n        %s\n", f.Name, f.Usage)
+	})
+}
@@ -0,0 +1,126 @@
+package main
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func Test_parseOptions(t *testing.T) {
+	tests := []struct {
+		name       string
+		args       []string
+		wantOpts   *options
+		wantGoTest []string
+		wantErr    error
+	}{
+		{
+			name:       "If there are no hottest options, pass all arguments to go test",
+			args:       []string{"-cover", "./...", "-run", "TestX"},
+			wantOpts:   &options{format: formatDots, shardBy: shardByPackage, parallel: 1, maxSkip: -1, notifyFormat: notifyFormatJSON},
+			wantGoTest: []string{"-cover", "./...", "-run", "TestX"},
+		},
+		{
+			name:       "Separate hottest options with '='",
+			args:       []string{"-cover", "-hottest.format=teamcity", "./..."},
+			wantOpts:   &options{format: formatTeamCity, shardBy: shardByPackage, parallel: 1, maxSkip: -1, notifyFormat: notifyFormatJSON},
+			wantGoTest: []string{"-cover", "./..."},
+		},
+		{
+			name:       "Separate hottest options with the value in the next argument",
+			args:       []string{"--hottest.format", "teamcity", "./..."},
+			wantOpts:   &options{format: formatTeamCity, shardBy: shardByPackage, parallel: 1, maxSkip: -1, notifyFormat: notifyFormatJSON},
+			wantGoTest: []string{"./..."},
+		},
+		{
+			name:       "Set the default value to the option whose value is omitted",
+			args:       []string{"-hottest.changed", "./...", "-hottest.history=.history"},
+			wantOpts:   &options{format: formatDots, shardBy: shardByPackage, parallel: 1, maxSkip: -1, notifyFormat: notifyFormatJSON, changed: defaultBaseRef, history: ".history"},
+			wantGoTest: []string{"./..."},
+		},
+		{
+			name:    "If the shard is invalid, return error",
+			args:    []string{"-hottest.shard=4/3", "./..."},
+			wantErr: errInvalidOption,
+		},
+		{
+			name:    "If parallel is less than 1, return error",
+			args:    []string{"-hottest.parallel=0", "./..."},
+			wantErr: errInvalidOption,
+		},
+		{
+			name:    "If the bench threshold is negative, return error",
+			args:    []string{"-hottest.bench-threshold=-0.1", "./..."},
+			wantErr: errInvalidOption,
+		},
+		{
+			name:    "If max skip is less than -1, return error",
+			args:    []string{"-hottest.max-skip=-2", "./..."},
+			wantErr: errInvalidOption,
+		},
+		{
+			name:    "If the skip pattern is invalid, return error",
+			args:    []string{"-hottest.fail-on-skip-pattern=(", "./..."},
+			wantErr: errInvalidOption,
+		},
+		{
+			name:    "If groups are used with shard, return error",
+			args:    []string{"-hottest.groups=groups.json", "-hottest.shard=1/2", "./..."},
+			wantErr: errInvalidOption,
+		},
+		{
+			name:    "If -run is used with the test sharding, return error",
+			args:    []string{"-hottest.shard=1/2", "-hottest.shard-by=test", "-run", "TestX", "./..."},
+			wantErr: errInvalidOption,
+		},
+		{
+			name:    "If -test.run is used with the test sharding, return error",
+			args:    []string{"-hottest.shard=1/2", "-hottest.shard-by=test", "-test.run", "TestX", "./..."},
+			wantErr: errInvalidOption,
+		},
+		{
+			name:    "If the format is unknown, return error",
+			args:    []string{"-hottest.format=xml", "./..."},
+			wantErr: errInvalidOption,
+		},
+		{
+			name:    "If the option is undefined, return error",
+			args:    []string{"-hottest.undefined=1", "./..."},
+			wantErr: errInvalidOption,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("TEAMCITY_VERSION", "")
+
+			gotOpts, gotGoTest, err := parseOptions(tt.args)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("parseOptions() error = %v, want %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if diff := cmp.Diff(tt.wantOpts, gotOpts, cmp.AllowUnexported(options{})); diff != "" {
+				t.Errorf("options mismatch (-want +got):\n%s", diff)
+			}
+			if diff := cmp.Diff(tt.wantGoTest, gotGoTest); diff != "" {
+				t.Errorf("go test arguments mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+
+	t.Run("Use teamcity format on TeamCity", func(t *testing.T) {
+		t.Setenv("GITHUB_ACTIONS", "")
+		t.Setenv("TEAMCITY_VERSION", "2023.05")
+
+		opts, _, err := parseOptions([]string{"./..."})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if opts.format != formatTeamCity {
+			t.Errorf("format = %s, want %s", opts.format, formatTeamCity)
+		}
+	})
+}
@@ -0,0 +1,59 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/nao1215/hottest/testjson"
+)
+
+// The output of 'go test -json' is processed by a pipeline:
+//
+//	reader goroutine (lines) -> decoder goroutine (events) -> aggregator -> reporters
+//
+// The reader and the decoder are provided by testjson.Decode. Its channel is closed
+// at the end of the stream, so all events have been aggregated when consume returns.
+
+// consume consumes the output of the test command until the end of the stream.
+// The events are aggregated in the calling goroutine, so the results are complete when it returns.
+func (h *hottest) consume(r io.Reader) {
+	for e := range testjson.Decode(r) {
+		if err := h.handle(e); err != nil {
+			fmt.Fprintln(os.Stderr, err.Error())
+		}
+	}
+}
+
+// parse parses a line of test output and handles the event.
+func (h *hottest) parse(line string) error {
+	return h.handle(testjson.DecodeLine(line))
+}
+
+// handle saves the event to the log, passes it to the aggregator and then to the reporters.
+// It returns the error of the event if the line is not a JSON or the output cannot be read.
+func (h *hottest) handle(e testjson.Event) error {
+	if h.log != nil && (e.Err == nil || e.Line != "") {
+		fmt.Fprintln(h.log, e.Line)
+	}
+	if e.Err != nil {
+		return e.Err
+	}
+	h.Add(e.Output)
+	for _, r := range h.reporters {
+		r.OnEvent(e.Output)
+	}
+
+	o := e.Output
+	if o.Test == "" {
+		return nil
+	}
+	switch o.Action {
+	case testjson.ActionPass, testjson.ActionFail, testjson.ActionSkip:
+		t := h.Results.Find(o.Package, o.Test)
+		for _, r := range h.reporters {
+			r.OnTestFinished(t)
+		}
+	}
+	return nil
+}
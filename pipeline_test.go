@@ -0,0 +1,88 @@
+package main
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/nao1215/hottest/testjson"
+)
+
+func Test_consume(t *testing.T) {
+	t.Run("All events are aggregated before consume returns", func(t *testing.T) {
+		b, err := os.ReadFile("testdata/sample.json")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		r, w := io.Pipe()
+		go func() {
+			// Write the log in small chunks so that the lines are split across reads.
+			for i := 0; i < len(b); i += 7 {
+				end := i + 7
+				if end > len(b) {
+					end = len(b)
+				}
+				if _, err := w.Write(b[i:end]); err != nil {
+					t.Error(err)
+					return
+				}
+			}
+			w.Close() //nolint
+		}()
+
+		var log, dots bytes.Buffer
+		h := newHottestForLog()
+		h.log = &log
+		h.reporters = []Reporter{dotReporter{w: &dots}}
+		h.consume(r)
+
+		if diff := cmp.Diff(testjson.TestStats{Pass: 3, Fail: 2, Skip: 1, Total: 6}, h.Stats); diff != "" {
+			t.Errorf("stats mismatch (-want +got):\n%s", diff)
+		}
+		if !bytes.Equal(b, log.Bytes()) {
+			t.Errorf("log mismatch: got %d bytes, want %d bytes", log.Len(), len(b))
+		}
+		if got := strings.Count(dots.String(), "."); got != 6 {
+			t.Errorf("printed %d dots, want 6", got)
+		}
+		if len(testjson.ExtractFailTestMessage(h.Messages)) == 0 {
+			t.Error("error messages are not aggregated")
+		}
+	})
+
+	t.Run("Consume multiple streams concurrently with shared writers", func(t *testing.T) {
+		var log bytes.Buffer
+		parent := newHottestForLog()
+		parent.log = &lockedWriter{w: &log}
+
+		children := make([]*hottest, 4)
+		done := make(chan struct{})
+		for i := range children {
+			children[i] = parent.newChild(nil)
+			go func(child *hottest) {
+				defer func() { done <- struct{}{} }()
+				f, err := os.Open("testdata/sample.json")
+				if err != nil {
+					t.Error(err)
+					return
+				}
+				defer f.Close() //nolint
+				child.consume(f)
+			}(children[i])
+		}
+		for range children {
+			<-done
+		}
+		for _, child := range children {
+			parent.Merge("", child.Aggregator)
+		}
+
+		if diff := cmp.Diff(testjson.TestStats{Pass: 12, Fail: 8, Skip: 4, Total: 24}, parent.Stats); diff != "" {
+			t.Errorf("stats mismatch (-want +got):\n%s", diff)
+		}
+	})
+}
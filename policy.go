@@ -0,0 +1,52 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/nao1215/hottest/testjson"
+)
+
+// errPolicyViolation is an error that occurs when the test results violate the policy options
+// (-hottest.max-skip, -hottest.fail-on-skip-pattern and -hottest.require-tests).
+var errPolicyViolation = errors.New("test policy violation")
+
+// checkPolicy returns errPolicyViolation if the test results violate the policy options.
+// All violations are reported in the error message.
+func (h *hottest) checkPolicy() error {
+	violations := []string{}
+
+	if h.opts.requireTests && h.Stats.Pass+h.Stats.Fail == 0 && len(h.Results.Benchmarks()) == 0 {
+		violations = append(violations, "no tests ran")
+	}
+
+	if h.opts.maxSkip >= 0 && int(h.Stats.Skip) > h.opts.maxSkip {
+		violations = append(violations,
+			fmt.Sprintf("%d tests are skipped, more than -%smax-skip=%d", h.Stats.Skip, optionPrefix, h.opts.maxSkip))
+	}
+
+	if h.opts.failOnSkipPattern != "" {
+		pattern := regexp.MustCompile(h.opts.failOnSkipPattern) // The pattern is validated by parseOptions.
+		for _, t := range h.Results.Tests(testjson.ActionSkip) {
+			if reason := skipReason(t); pattern.MatchString(reason) {
+				violations = append(violations, fmt.Sprintf("%s%s (%s) is skipped: %s", t.Label(), t.Name, t.Package, reason))
+			}
+		}
+	}
+
+	if len(violations) == 0 {
+		return nil
+	}
+	return fmt.Errorf("%w: %s", errPolicyViolation, strings.Join(violations, "; "))
+}
+
+// checkNoTestsToRun returns errPolicyViolation if -hottest.require-tests is specified
+// for the run that ends without running the tests, e.g. no packages are affected by the changes.
+func (h *hottest) checkNoTestsToRun(reason string) error {
+	if !h.opts.requireTests {
+		return nil
+	}
+	return fmt.Errorf("%w: no tests ran: %s", errPolicyViolation, reason)
+}
@@ -0,0 +1,150 @@
+package main
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func Test_checkPolicy(t *testing.T) {
+	tests := []struct {
+		name     string
+		log      string
+		opts     options
+		wantErr  error
+		wantMsgs []string
+	}{
+		{
+			name: "If no policy options are specified, return nil",
+			log:  "testdata/skip.json",
+			opts: options{maxSkip: -1},
+		},
+		{
+			name: "If the skipped tests are not more than max skip, return nil",
+			log:  "testdata/skip.json",
+			opts: options{maxSkip: 4},
+		},
+		{
+			name:     "If the skipped tests are more than max skip, return error",
+			log:      "testdata/skip.json",
+			opts:     options{maxSkip: 3},
+			wantErr:  errPolicyViolation,
+			wantMsgs: []string{"4 tests are skipped, more than -hottest.max-skip=3"},
+		},
+		{
+			name:    "If the reason of a skipped test matches the pattern, return error",
+			log:     "testdata/skip.json",
+			opts:    options{maxSkip: -1, failOnSkipPattern: "DATABASE_URL"},
+			wantErr: errPolicyViolation,
+			wantMsgs: []string{
+				"TestDB (example.com/sample/skip) is skipped: skip_test.go:10: DATABASE_URL is not set",
+				"TestCache (example.com/sample/skip) is skipped: skip_test.go:16: DATABASE_URL is not set",
+			},
+		},
+		{
+			name:    "If no skip reason matches the pattern, return nil",
+			log:     "testdata/skip.json",
+			opts:    options{maxSkip: -1, failOnSkipPattern: "^integration"},
+			wantErr: nil,
+		},
+		{
+			name: "If tests ran, require tests returns nil",
+			log:  "testdata/skip.json",
+			opts: options{maxSkip: -1, requireTests: true},
+		},
+		{
+			name:     "If no tests ran, require tests returns error",
+			log:      "testdata/empty.json",
+			opts:     options{maxSkip: -1, requireTests: true},
+			wantErr:  errPolicyViolation,
+			wantMsgs: []string{"no tests ran"},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			h := newHottestFromLog(t, tt.log)
+			h.opts = &tt.opts
+
+			err := h.checkPolicy()
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("checkPolicy() error = %v, want %v", err, tt.wantErr)
+			}
+			for _, want := range tt.wantMsgs {
+				if !strings.Contains(err.Error(), want) {
+					t.Errorf("checkPolicy() error = %q, does not contain %q", err.Error(), want)
+				}
+			}
+		})
+	}
+}
+
+func Test_run_requireTests(t *testing.T) {
+	t.Run("Fail if the shard has no tests", func(t *testing.T) {
+		args := []string{"hottest", "-hottest.shard=2/2", "./testjson"}
+		if err := run(args); err != nil {
+			t.Errorf("run() error = %v, want nil", err)
+		}
+		err := run(append([]string{args[0], "-hottest.require-tests"}, args[1:]...))
+		if !errors.Is(err, errPolicyViolation) {
+			t.Errorf("run() error = %v, want %v", err, errPolicyViolation)
+		}
+	})
+
+	t.Run("Fail if no packages are affected by the changes", func(t *testing.T) {
+		if _, err := exec.LookPath("git"); err != nil {
+			t.Skip("git is not installed")
+		}
+
+		dir := t.TempDir()
+		t.Setenv("GIT_CONFIG_GLOBAL", os.DevNull)
+		t.Setenv("GIT_CONFIG_NOSYSTEM", "1")
+		files := map[string]string{
+			"go.mod":      "module example.com/m\n\ngo 1.19\n",
+			"a/a_test.go": "package a\n\nimport \"testing\"\n\nfunc TestA(t *testing.T) {}\n",
+			"README.md":   "a\n",
+		}
+		for name, content := range files {
+			if err := os.MkdirAll(filepath.Join(dir, filepath.Dir(name)), 0o750); err != nil {
+				t.Fatal(err)
+			}
+			if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o600); err != nil {
+				t.Fatal(err)
+			}
+		}
+		for _, args := range [][]string{
+			{"init", "-q"},
+			{"add", "-A"},
+			{"-c", "user.name=test", "-c", "user.email=test@example.com", "commit", "-q", "-m", "initial"},
+		} {
+			cmd := exec.Command("git", args...)
+			cmd.Dir = dir
+			if out, err := cmd.CombinedOutput(); err != nil {
+				t.Fatalf("git %v: %s: %s", args, err, out)
+			}
+		}
+		if err := os.WriteFile(filepath.Join(dir, "README.md"), []byte("b\n"), 0o600); err != nil {
+			t.Fatal(err)
+		}
+
+		wd, err := os.Getwd()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := os.Chdir(dir); err != nil {
+			t.Fatal(err)
+		}
+		defer os.Chdir(wd) //nolint
+
+		if err := run([]string{"hottest", "-hottest.changed=HEAD", "./..."}); err != nil {
+			t.Errorf("run() error = %v, want nil", err)
+		}
+		err = run([]string{"hottest", "-hottest.require-tests", "-hottest.changed=HEAD", "./..."})
+		if !errors.Is(err, errPolicyViolation) {
+			t.Errorf("run() error = %v, want %v", err, errPolicyViolation)
+		}
+	})
+}
@@ -0,0 +1,40 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// process is a process that is left in the process group of the test command.
+type process struct {
+	pid  int
+	name string
+}
+
+// String returns the PID and the command name, e.g. "1234 (sleep)".
+func (p process) String() string {
+	return fmt.Sprintf("%d (%s)", p.pid, p.name)
+}
+
+// cleanupProcessGroup kills the processes that are left in the process group of the test command
+// after it exits, e.g. the servers that the tests started and the test binaries that go test did not stop.
+// The killed processes are reported to w because they are likely to be bugs of the tests.
+func cleanupProcessGroup(w io.Writer, cmd *exec.Cmd) {
+	leftovers := groupProcesses(cmd)
+	if len(leftovers) == 0 {
+		return
+	}
+	if err := killProcessGroup(cmd); err != nil && !errors.Is(err, os.ErrProcessDone) {
+		fmt.Fprintf(w, "failed to kill the leftover processes: %s\n", err.Error())
+		return
+	}
+	names := make([]string, 0, len(leftovers))
+	for _, p := range leftovers {
+		names = append(names, p.String())
+	}
+	fmt.Fprintf(w, "\nhottest: killed %d leftover processes of 'go test': %s\n", len(leftovers), strings.Join(names, ", "))
+}
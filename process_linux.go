@@ -0,0 +1,81 @@
+package main
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
+)
+
+// setProcessGroup makes the test command the leader of a new process group, so that the signals
+// reach the test binaries that go test spawns and the whole tree can be killed.
+func setProcessGroup(cmd *exec.Cmd) {
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+}
+
+// signalProcessGroup sends the signal to the process group of the test command.
+func signalProcessGroup(cmd *exec.Cmd, sig os.Signal) error {
+	s, ok := sig.(syscall.Signal)
+	if !ok {
+		return cmd.Process.Signal(sig)
+	}
+	if err := syscall.Kill(-cmd.Process.Pid, s); err != nil {
+		if err == syscall.ESRCH {
+			return os.ErrProcessDone
+		}
+		return err
+	}
+	return nil
+}
+
+// killProcessGroup kills the processes in the process group of the test command.
+func killProcessGroup(cmd *exec.Cmd) error {
+	return signalProcessGroup(cmd, syscall.SIGKILL)
+}
+
+// groupProcesses returns the processes that are still alive in the process group of the test command,
+// e.g. the processes that the tests started and did not stop. Zombies are omitted.
+func groupProcesses(cmd *exec.Cmd) []process {
+	stats, err := filepath.Glob("/proc/[0-9]*/stat")
+	if err != nil {
+		return nil
+	}
+	processes := []process{}
+	for _, path := range stats {
+		b, err := os.ReadFile(filepath.Clean(path))
+		if err != nil {
+			continue // The process has exited.
+		}
+		p, pgid, ok := parseProcStat(string(b))
+		if ok && pgid == cmd.Process.Pid {
+			processes = append(processes, p)
+		}
+	}
+	return processes
+}
+
+// parseProcStat parses /proc/[pid]/stat, e.g. "1234 (sleep) S 1 1200 ...", and returns the process
+// and its process group ID. It returns false if the stat is broken or the process is a zombie.
+// The command name is enclosed in the parentheses because it may contain spaces.
+func parseProcStat(stat string) (process, int, bool) {
+	open, end := strings.IndexByte(stat, '('), strings.LastIndexByte(stat, ')')
+	if open < 0 || end < open {
+		return process{}, 0, false
+	}
+	pid, err := strconv.Atoi(strings.TrimSpace(stat[:open]))
+	if err != nil {
+		return process{}, 0, false
+	}
+	// The fields after the command name are the state, the parent PID and the process group ID.
+	fields := strings.Fields(stat[end+1:])
+	if len(fields) < 3 || fields[0] == "Z" {
+		return process{}, 0, false
+	}
+	pgid, err := strconv.Atoi(fields[2])
+	if err != nil {
+		return process{}, 0, false
+	}
+	return process{pid: pid, name: stat[open+1 : end]}, pgid, true
+}
@@ -0,0 +1,89 @@
+package main
+
+import (
+	"os/exec"
+	"strings"
+	"testing"
+	"time"
+)
+
+func Test_parseProcStat(t *testing.T) {
+	tests := []struct {
+		name     string
+		stat     string
+		want     process
+		wantPgid int
+		wantOK   bool
+	}{
+		{
+			name:     "Parse the PID, the command name and the process group ID",
+			stat:     "1234 (sleep) S 1200 1200 1100 0 -1 4194560 96 0 0 0",
+			want:     process{pid: 1234, name: "sleep"},
+			wantPgid: 1200,
+			wantOK:   true,
+		},
+		{
+			name:     "The command name may contain spaces and parentheses",
+			stat:     "1234 (my (server) x) S 1200 1201 1100 0",
+			want:     process{pid: 1234, name: "my (server) x"},
+			wantPgid: 1201,
+			wantOK:   true,
+		},
+		{
+			name: "Omit the zombie",
+			stat: "1234 (sleep) Z 1 1200 1100 0",
+		},
+		{
+			name: "Omit the broken stat",
+			stat: "1234 sleep",
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			got, pgid, ok := parseProcStat(tt.stat)
+			if got != tt.want || pgid != tt.wantPgid || ok != tt.wantOK {
+				t.Errorf("parseProcStat() = %v, %d, %t, want %v, %d, %t", got, pgid, ok, tt.want, tt.wantPgid, tt.wantOK)
+			}
+		})
+	}
+}
+
+func Test_cleanupProcessGroup(t *testing.T) {
+	t.Run("Kill and report the processes that are left in the process group", func(t *testing.T) {
+		cmd := exec.Command("sh", "-c", "sleep 30 & exit 0")
+		setProcessGroup(cmd)
+		if err := cmd.Run(); err != nil {
+			t.Fatal(err)
+		}
+
+		var b strings.Builder
+		cleanupProcessGroup(&b, cmd)
+		if !strings.Contains(b.String(), "killed 1 leftover processes of 'go test'") || !strings.Contains(b.String(), "(sleep)") {
+			t.Errorf("the leftover process is not reported: %q", b.String())
+		}
+
+		// The killed process becomes a zombie or disappears.
+		deadline := time.Now().Add(5 * time.Second)
+		for len(groupProcesses(cmd)) > 0 {
+			if time.Now().After(deadline) {
+				t.Fatalf("the leftover processes are alive: %v", groupProcesses(cmd))
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+	})
+
+	t.Run("Report nothing if no processes are left", func(t *testing.T) {
+		cmd := exec.Command("sh", "-c", "exit 0")
+		setProcessGroup(cmd)
+		if err := cmd.Run(); err != nil {
+			t.Fatal(err)
+		}
+
+		var b strings.Builder
+		cleanupProcessGroup(&b, cmd)
+		if b.String() != "" {
+			t.Errorf("cleanupProcessGroup() reports %q, want nothing", b.String())
+		}
+	})
+}
@@ -0,0 +1,27 @@
+//go:build !linux
+
+package main
+
+import (
+	"os"
+	"os/exec"
+)
+
+// setProcessGroup does nothing because the process group is managed only on Linux.
+func setProcessGroup(*exec.Cmd) {}
+
+// signalProcessGroup sends the signal to the test command. The test binaries that go test spawns
+// do not receive it unless they are in the same process group as hottest, e.g. on Ctrl-C.
+func signalProcessGroup(cmd *exec.Cmd, sig os.Signal) error {
+	return cmd.Process.Signal(sig)
+}
+
+// killProcessGroup kills the test command.
+func killProcessGroup(cmd *exec.Cmd) error {
+	return cmd.Process.Kill()
+}
+
+// groupProcesses returns nil because the leftover processes are found only on Linux.
+func groupProcesses(*exec.Cmd) []process {
+	return nil
+}
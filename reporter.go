@@ -0,0 +1,238 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/nao1215/hottest/testjson"
+)
+
+// Reporter receives the progress and the results of the tests.
+// The methods may be called concurrently when the 'go test' commands run in parallel
+// (-hottest.parallel), so a Reporter must be safe for concurrent use.
+type Reporter interface {
+	// OnStart is called before the first 'go test' command starts.
+	OnStart()
+	// OnEvent is called for each event of 'go test -json' after the results are updated.
+	OnEvent(e testjson.TestOutputJSON)
+	// OnTestFinished is called when a test passes, fails or is skipped.
+	OnTestFinished(t *testjson.TestResult)
+	// OnFinish is called after all tests finish.
+	OnFinish(s Summary)
+}
+
+// Summary is the summary of the test run that is passed to the reporters.
+type Summary struct {
+	// Stats is the test statistics.
+	Stats testjson.TestStats `json:"stats"`
+	// Started is the time when the run started.
+	Started time.Time `json:"started"`
+	// Finished is the time when the run finished.
+	Finished time.Time `json:"finished"`
+	// Elapsed is the elapsed time of the run in seconds.
+	Elapsed float64 `json:"elapsed"`
+	// FailMessages is the error messages of the failed tests that are printed in [Error Messages].
+	FailMessages []string `json:"fail_messages"`
+	// Failures is the failed tests that have their own error messages.
+	Failures []reporterTest `json:"failures"`
+	// Benchmarks is the benchmark results.
+	Benchmarks []*testjson.Benchmark `json:"benchmarks"`
+	// Interrupted is true if the run was interrupted by a signal. The results are of the completed tests.
+	Interrupted bool `json:"interrupted"`
+	// Outcome is the outcome of the run: "pass", "fail", "build_failure", "timeout", "interrupted" or "error".
+	// The stats of a run that failed to build or timed out do not tell the failure.
+	Outcome string `json:"outcome"`
+}
+
+// summary returns the summary of the test run that ended with err.
+func (h *hottest) summary(err error) Summary {
+	failures := []reporterTest{}
+	for _, t := range h.Results.Failures() {
+		failures = append(failures, newReporterTest(t))
+	}
+	return Summary{
+		Stats:        h.Stats,
+		Started:      h.interval.Started,
+		Finished:     h.interval.Finished,
+		Elapsed:      h.interval.Duration().Seconds(),
+		FailMessages: h.FailMessages(),
+		Failures:     failures,
+		Benchmarks:   h.Results.Benchmarks(),
+		Interrupted:  h.interrupt.interrupted(),
+		Outcome:      runOutcome(err),
+	}
+}
+
+// startReporters calls OnStart of the reporters.
+func (h *hottest) startReporters() {
+	for _, r := range h.reporters {
+		r.OnStart()
+	}
+}
+
+// finishReporters calls OnFinish of the reporters with the summary of the run that ended with err.
+func (h *hottest) finishReporters(err error) {
+	s := h.summary(err)
+	for _, r := range h.reporters {
+		r.OnFinish(s)
+	}
+}
+
+// dotReporter prints a colored dot that represents the test result.
+type dotReporter struct {
+	w io.Writer
+}
+
+// OnStart does nothing.
+func (d dotReporter) OnStart() {}
+
+// OnEvent prints a dot if the event is the result line of a test, e.g. "--- PASS: TestX".
+func (d dotReporter) OnEvent(o testjson.TestOutputJSON) {
+	trimmed := strings.TrimSpace(o.Output)
+	switch {
+	case strings.HasPrefix(trimmed, "--- PASS"):
+		fmt.Fprint(d.w, color.GreenString("."))
+	case strings.HasPrefix(trimmed, "--- SKIP"):
+		fmt.Fprint(d.w, color.BlueString("."))
+	case strings.HasPrefix(trimmed, "--- FAIL"):
+		fmt.Fprint(d.w, color.RedString("."))
+	}
+}
+
+// OnTestFinished does nothing because the dot is printed for the result line.
+func (d dotReporter) OnTestFinished(*testjson.TestResult) {}
+
+// OnFinish does nothing.
+func (d dotReporter) OnFinish(Summary) {}
+
+// reporterMessage is a JSON line that is written to the stdin of the external reporter.
+type reporterMessage struct {
+	// Type is "start", "event", "test_finished" or "finish".
+	Type    string                   `json:"type"`
+	Event   *testjson.TestOutputJSON `json:"event,omitempty"`
+	Test    *reporterTest            `json:"test,omitempty"`
+	Summary *Summary                 `json:"summary,omitempty"`
+}
+
+// reporterTest is the result of a test that is written to the stdin of the external reporter.
+type reporterTest struct {
+	Source   string   `json:"source,omitempty"`
+	Package  string   `json:"package"`
+	Name     string   `json:"name"`
+	Action   string   `json:"action"`
+	Elapsed  float64  `json:"elapsed"`
+	Messages []string `json:"messages"`
+}
+
+// newReporterTest returns the result of the test that is passed to the reporters.
+func newReporterTest(t *testjson.TestResult) reporterTest {
+	return reporterTest{
+		Source:   t.Source,
+		Package:  t.Package,
+		Name:     t.Name,
+		Action:   t.Action,
+		Elapsed:  t.Elapsed,
+		Messages: t.Messages(),
+	}
+}
+
+// externalReporter is a reporter that runs a command and writes the events
+// to its stdin as JSON lines. The failure of the command does not change the
+// result of hottest; it is printed to stderr.
+type externalReporter struct {
+	mu      sync.Mutex
+	command string
+	cmd     *exec.Cmd
+	stdin   io.WriteCloser
+	enc     *json.Encoder
+	failed  bool
+}
+
+// newExternalReporter starts the command of the external reporter, e.g. "python3 notify.py".
+// The command is split by spaces and is not run by a shell.
+func newExternalReporter(command string) (*externalReporter, error) {
+	fields := strings.Fields(command)
+	if len(fields) == 0 {
+		return nil, fmt.Errorf("%w: empty reporter command", errInvalidOption)
+	}
+	cmd := exec.Command(fields[0], fields[1:]...) //#nosec
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return nil, fmt.Errorf("failed to start reporter '%s': %w", command, err)
+	}
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("failed to start reporter '%s': %w", command, err)
+	}
+	return &externalReporter{command: command, cmd: cmd, stdin: stdin, enc: json.NewEncoder(stdin)}, nil
+}
+
+// newExternalReporters starts the commands of the external reporters.
+// If a command fails to start, the reporters that have already started are closed.
+func newExternalReporters(commands []string) ([]*externalReporter, error) {
+	reporters := make([]*externalReporter, 0, len(commands))
+	for _, command := range commands {
+		r, err := newExternalReporter(command)
+		if err != nil {
+			for _, started := range reporters {
+				started.close()
+			}
+			return nil, err
+		}
+		reporters = append(reporters, r)
+	}
+	return reporters, nil
+}
+
+// OnStart writes the start message.
+func (e *externalReporter) OnStart() {
+	e.write(reporterMessage{Type: "start"})
+}
+
+// OnEvent writes the event message.
+func (e *externalReporter) OnEvent(o testjson.TestOutputJSON) {
+	e.write(reporterMessage{Type: "event", Event: &o})
+}
+
+// OnTestFinished writes the test_finished message.
+func (e *externalReporter) OnTestFinished(t *testjson.TestResult) {
+	rt := newReporterTest(t)
+	e.write(reporterMessage{Type: "test_finished", Test: &rt})
+}
+
+// OnFinish writes the finish message, closes the stdin and waits for the command to exit.
+func (e *externalReporter) OnFinish(s Summary) {
+	e.write(reporterMessage{Type: "finish", Summary: &s})
+	e.close()
+}
+
+// close closes the stdin and waits for the command to exit.
+func (e *externalReporter) close() {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.stdin.Close() //nolint
+	if err := e.cmd.Wait(); err != nil && !e.failed {
+		fmt.Fprintf(os.Stderr, "reporter '%s' failed: %s\n", e.command, err.Error())
+	}
+}
+
+// write writes the message as a JSON line. After the first error, the messages are discarded.
+func (e *externalReporter) write(m reporterMessage) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.failed {
+		return
+	}
+	if err := e.enc.Encode(m); err != nil {
+		e.failed = true
+		fmt.Fprintf(os.Stderr, "reporter '%s' failed: %s\n", e.command, err.Error())
+	}
+}
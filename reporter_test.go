@@ -0,0 +1,141 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/nao1215/hottest/testjson"
+)
+
+// recordReporter records the calls of the Reporter methods.
+type recordReporter struct {
+	mu    sync.Mutex
+	calls []string
+}
+
+func (r *recordReporter) record(call string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.calls = append(r.calls, call)
+}
+
+func (r *recordReporter) OnStart() { r.record("start") }
+
+func (r *recordReporter) OnEvent(e testjson.TestOutputJSON) {
+	if e.Action != testjson.ActionOutput {
+		r.record("event " + e.Action + " " + e.Test)
+	}
+}
+
+func (r *recordReporter) OnTestFinished(t *testjson.TestResult) {
+	r.record("finished " + t.Action + " " + t.Name)
+}
+
+func (r *recordReporter) OnFinish(s Summary) { r.record(fmt.Sprintf("finish %d", s.Stats.Fail)) }
+
+func Test_hottest_handle(t *testing.T) {
+	t.Run("Pass the events and the finished tests to the reporters", func(t *testing.T) {
+		rec := &recordReporter{}
+		h := newHottestForLog()
+		h.reporters = []Reporter{rec}
+		h.startReporters()
+		for _, line := range []string{
+			`{"Action":"run","Package":"a","Test":"TestA"}`,
+			`{"Action":"output","Package":"a","Test":"TestA","Output":"--- FAIL: TestA (0.00s)\n"}`,
+			`{"Action":"fail","Package":"a","Test":"TestA"}`,
+			`{"Action":"fail","Package":"a"}`,
+		} {
+			if err := h.parse(line); err != nil {
+				t.Fatal(err)
+			}
+		}
+		h.finishReporters(errFailTest)
+
+		want := []string{
+			"start",
+			"event run TestA",
+			"event fail TestA",
+			"finished fail TestA",
+			"event fail ",
+			"finish 1",
+		}
+		if diff := cmp.Diff(want, rec.calls); diff != "" {
+			t.Errorf("calls mismatch (-want +got):\n%s", diff)
+		}
+	})
+}
+
+func Test_externalReporter(t *testing.T) {
+	tee, err := exec.LookPath("tee")
+	if err != nil {
+		t.Skip("tee command is not available")
+	}
+	path := filepath.Join(t.TempDir(), "events.json")
+	r, err := newExternalReporter(tee + " " + path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	r.OnStart()
+	r.OnEvent(testjson.TestOutputJSON{Action: testjson.ActionRun, Package: "a", Test: "TestA"})
+	r.OnTestFinished(&testjson.TestResult{Package: "a", Name: "TestA", Action: testjson.ActionFail, Output: []string{"    a_test.go:1: ng"}})
+	r.OnFinish(Summary{Stats: testjson.TestStats{Fail: 1, Total: 1}, Failures: []reporterTest{}})
+
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close() //nolint
+	got := []reporterMessage{}
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var m reporterMessage
+		if err := json.Unmarshal(scanner.Bytes(), &m); err != nil {
+			t.Fatal(err)
+		}
+		got = append(got, m)
+	}
+
+	want := []reporterMessage{
+		{Type: "start"},
+		{Type: "event", Event: &testjson.TestOutputJSON{Action: testjson.ActionRun, Package: "a", Test: "TestA"}},
+		{Type: "test_finished", Test: &reporterTest{Package: "a", Name: "TestA", Action: testjson.ActionFail, Messages: []string{"    a_test.go:1: ng"}}},
+		{Type: "finish", Summary: &Summary{Stats: testjson.TestStats{Fail: 1, Total: 1}, Failures: []reporterTest{}}},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("messages mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func Test_newExternalReporters(t *testing.T) {
+	tee, err := exec.LookPath("tee")
+	if err != nil {
+		t.Skip("tee command is not available")
+	}
+
+	t.Run("close the started reporters if a command fails to start", func(t *testing.T) {
+		if _, err := newExternalReporters([]string{tee, "hottest-no-such-reporter"}); err == nil {
+			t.Error("newExternalReporters() should fail")
+		}
+	})
+
+	t.Run("close waits for the command to exit", func(t *testing.T) {
+		rs, err := newExternalReporters([]string{tee, tee})
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, r := range rs {
+			r.close()
+			if r.cmd.ProcessState == nil || !r.cmd.ProcessState.Exited() {
+				t.Errorf("reporter '%s' did not exit", r.command)
+			}
+		}
+	})
+}
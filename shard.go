@@ -0,0 +1,250 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os/exec"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/nao1215/hottest/testjson"
+)
+
+const (
+	// shardByPackage splits the packages across the shards.
+	shardByPackage = "package"
+	// shardByTest splits the top-level tests across the shards.
+	shardByTest = "test"
+)
+
+// shard is the shard that this run executes.
+type shard struct {
+	// index is the 1-based index of the shard.
+	index int
+	// total is the number of the shards.
+	total int
+}
+
+// parseShard parses the shard in the format "i/n", e.g. "1/3".
+func parseShard(s string) (shard, error) {
+	parts := strings.Split(s, "/")
+	if len(parts) != 2 {
+		return shard{}, fmt.Errorf("%w: shard must be in the format i/n: %s", errInvalidOption, s)
+	}
+	index, err := strconv.Atoi(parts[0])
+	if err != nil {
+		return shard{}, fmt.Errorf("%w: invalid shard index: %s", errInvalidOption, s)
+	}
+	total, err := strconv.Atoi(parts[1])
+	if err != nil {
+		return shard{}, fmt.Errorf("%w: invalid shard total: %s", errInvalidOption, s)
+	}
+	if total < 1 || index < 1 || index > total {
+		return shard{}, fmt.Errorf("%w: shard index must be between 1 and %d: %s", errInvalidOption, total, s)
+	}
+	return shard{index: index, total: total}, nil
+}
+
+// shardUnit is a unit of the tests that is assigned to a shard.
+type shardUnit struct {
+	// pkg is the import path of the package.
+	pkg string
+	// test is the name of the top-level test. It is empty if the unit is the whole package.
+	test string
+	// duration is the estimated duration in seconds.
+	duration float64
+}
+
+// assignShards splits the units into n shards so that the total durations of the shards are balanced.
+// From the longest unit, each unit is assigned to the shard with the shortest total duration.
+// The result is deterministic for the same units.
+func assignShards(units []shardUnit, n int) [][]shardUnit {
+	sorted := make([]shardUnit, len(units))
+	copy(sorted, units)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if sorted[i].duration != sorted[j].duration {
+			return sorted[i].duration > sorted[j].duration
+		}
+		if sorted[i].pkg != sorted[j].pkg {
+			return sorted[i].pkg < sorted[j].pkg
+		}
+		return sorted[i].test < sorted[j].test
+	})
+
+	shards := make([][]shardUnit, n)
+	totals := make([]float64, n)
+	for _, u := range sorted {
+		shortest := 0
+		for i := 1; i < n; i++ {
+			// If the total durations are the same (e.g. all tests finish in a moment),
+			// the shard with fewer units is chosen.
+			if totals[i] < totals[shortest] ||
+				(totals[i] == totals[shortest] && len(shards[i]) < len(shards[shortest])) {
+				shortest = i
+			}
+		}
+		shards[shortest] = append(shards[shortest], u)
+		totals[shortest] += u.duration
+	}
+	return shards
+}
+
+// estimateDurations sets the durations of the previous run to the units.
+// The units that were not run previously are estimated as the average duration.
+func estimateDurations(units []shardUnit, previous *testjson.Results) {
+	known := map[string]float64{}
+	if previous != nil {
+		for _, p := range previous.Packages {
+			known[p.Name] = p.Elapsed
+			for _, t := range p.Tests {
+				if !strings.Contains(t.Name, "/") {
+					known[p.Name+" "+t.Name] = t.Elapsed
+				}
+			}
+		}
+	}
+
+	sum, n := 0.0, 0
+	for i, u := range units {
+		key := u.pkg
+		if u.test != "" {
+			key = u.pkg + " " + u.test
+		}
+		if d, ok := known[key]; ok {
+			units[i].duration = d
+			sum += d
+			n++
+		} else {
+			units[i].duration = -1
+		}
+	}
+
+	avg := 1.0
+	if n > 0 && sum > 0 {
+		avg = sum / float64(n)
+	}
+	for i := range units {
+		if units[i].duration < 0 {
+			units[i].duration = avg
+		}
+	}
+}
+
+// shardInvocations returns the 'go test' arguments that run the tests of the shard.
+// In the package mode, it returns an argument list with the packages of the shard.
+// In the test mode, it returns an argument list for each package with the -run flag
+// that selects the tests of the shard.
+func shardInvocations(args []string, opts *options) ([][]string, error) {
+	s, err := parseShard(opts.shard)
+	if err != nil {
+		return nil, err
+	}
+
+	var previous *testjson.Results
+	if opts.shardTiming != "" {
+		h, err := readLog(opts.shardTiming)
+		if err != nil {
+			return nil, err
+		}
+		previous = h.Results
+	}
+
+	flags, patterns := splitPackageArgs(args)
+	pkgs, err := goListPackages(flags, patterns)
+	if err != nil {
+		return nil, err
+	}
+	pkgNames := []string{}
+	for _, p := range pkgs {
+		if p.hasTests() {
+			pkgNames = append(pkgNames, p.ImportPath)
+		}
+	}
+
+	units := []shardUnit{}
+	switch opts.shardBy {
+	case shardByPackage:
+		for _, p := range pkgNames {
+			units = append(units, shardUnit{pkg: p})
+		}
+	case shardByTest:
+		if units, err = listTests(flags, pkgNames); err != nil {
+			return nil, err
+		}
+	}
+	estimateDurations(units, previous)
+	selected := assignShards(units, s.total)[s.index-1]
+	if len(selected) == 0 {
+		return [][]string{}, nil
+	}
+
+	if opts.shardBy == shardByPackage {
+		names := []string{}
+		for _, u := range selected {
+			names = append(names, u.pkg)
+		}
+		sort.Strings(names)
+		return [][]string{joinPackageArgs(flags, names)}, nil
+	}
+
+	tests := map[string][]string{}
+	order := []string{}
+	for _, u := range selected {
+		if _, ok := tests[u.pkg]; !ok {
+			order = append(order, u.pkg)
+		}
+		tests[u.pkg] = append(tests[u.pkg], regexp.QuoteMeta(u.test))
+	}
+	sort.Strings(order)
+	invocations := [][]string{}
+	for _, pkg := range order {
+		sort.Strings(tests[pkg])
+		run := fmt.Sprintf("-run=^(%s)$", strings.Join(tests[pkg], "|"))
+		invocations = append(invocations, joinPackageArgs(append(append([]string{}, flags...), run), []string{pkg}))
+	}
+	return invocations, nil
+}
+
+// listTests returns the top-level tests, examples and fuzz tests of the packages by 'go test -list'.
+func listTests(flags []string, pkgs []string) ([]shardUnit, error) {
+	if len(pkgs) == 0 {
+		return []shardUnit{}, nil
+	}
+	args := append([]string{"test", "-list", "."}, buildFlags(flags)...)
+	args = append(args, pkgs...)
+	out, err := exec.Command("go", args...).Output() //#nosec
+	if err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			return nil, fmt.Errorf("failed to list tests: %s%s", string(out), strings.TrimSpace(string(exitErr.Stderr)))
+		}
+		return nil, fmt.Errorf("failed to list tests: %w", err)
+	}
+	return parseTestList(strings.NewReader(string(out)))
+}
+
+// parseTestList parses the output of 'go test -list'. The test names are printed
+// before the "ok" line of each package.
+func parseTestList(r io.Reader) ([]shardUnit, error) {
+	units := []shardUnit{}
+	names := []string{}
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := scanner.Text()
+		fields := strings.Fields(line)
+		switch {
+		case len(fields) >= 2 && (fields[0] == "ok" || fields[0] == "?"):
+			for _, name := range names {
+				units = append(units, shardUnit{pkg: fields[1], test: name})
+			}
+			names = []string{}
+		case strings.HasPrefix(line, "Test") || strings.HasPrefix(line, "Example") || strings.HasPrefix(line, "Fuzz"):
+			names = append(names, strings.TrimSpace(line))
+		}
+	}
+	return units, scanner.Err()
+}
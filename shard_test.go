@@ -0,0 +1,112 @@
+package main
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/nao1215/hottest/testjson"
+)
+
+func Test_parseShard(t *testing.T) {
+	tests := []struct {
+		name    string
+		s       string
+		want    shard
+		wantErr bool
+	}{
+		{name: "Parse i/n", s: "2/3", want: shard{index: 2, total: 3}},
+		{name: "If the format is invalid, return error", s: "2", wantErr: true},
+		{name: "If the index is zero, return error", s: "0/3", wantErr: true},
+		{name: "If the index is greater than the total, return error", s: "4/3", wantErr: true},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseShard(tt.s)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseShard() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil && !errors.Is(err, errInvalidOption) {
+				t.Errorf("parseShard() error should be errInvalidOption: %v", err)
+			}
+			if diff := cmp.Diff(tt.want, got, cmp.AllowUnexported(shard{})); diff != "" {
+				t.Errorf("parseShard() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func Test_assignShards(t *testing.T) {
+	names := func(units []shardUnit) []string {
+		got := []string{}
+		for _, u := range units {
+			got = append(got, u.pkg)
+		}
+		return got
+	}
+
+	t.Run("Balance the shards with the durations", func(t *testing.T) {
+		units := []shardUnit{
+			{pkg: "a", duration: 1}, {pkg: "b", duration: 8}, {pkg: "c", duration: 3},
+			{pkg: "d", duration: 4}, {pkg: "e", duration: 2},
+		}
+		got := assignShards(units, 2)
+		if diff := cmp.Diff([]string{"b", "a"}, names(got[0])); diff != "" {
+			t.Errorf("shard 1 mismatch (-want +got):\n%s", diff)
+		}
+		if diff := cmp.Diff([]string{"d", "c", "e"}, names(got[1])); diff != "" {
+			t.Errorf("shard 2 mismatch (-want +got):\n%s", diff)
+		}
+	})
+
+	t.Run("If the durations are the same, split the units evenly", func(t *testing.T) {
+		units := []shardUnit{{pkg: "a"}, {pkg: "b"}, {pkg: "c"}, {pkg: "d"}}
+		got := assignShards(units, 3)
+		if diff := cmp.Diff([][]string{{"a", "d"}, {"b"}, {"c"}}, [][]string{names(got[0]), names(got[1]), names(got[2])}); diff != "" {
+			t.Errorf("assignShards() mismatch (-want +got):\n%s", diff)
+		}
+	})
+}
+
+func Test_estimateDurations(t *testing.T) {
+	t.Run("Use the durations of the previous run and estimate the others as the average", func(t *testing.T) {
+		previous := testjson.NewResults()
+		previous.Record(testjson.TestOutputJSON{Action: testjson.ActionPass, Package: "p", Test: "TestA", Elapsed: 1})
+		previous.Record(testjson.TestOutputJSON{Action: testjson.ActionPass, Package: "p", Test: "TestA/sub", Elapsed: 1})
+		previous.Record(testjson.TestOutputJSON{Action: testjson.ActionPass, Package: "p", Test: "TestB", Elapsed: 3})
+
+		units := []shardUnit{{pkg: "p", test: "TestA"}, {pkg: "p", test: "TestB"}, {pkg: "p", test: "TestNew"}}
+		estimateDurations(units, previous)
+
+		got := []float64{}
+		for _, u := range units {
+			got = append(got, u.duration)
+		}
+		if diff := cmp.Diff([]float64{1, 3, 2}, got); diff != "" {
+			t.Errorf("durations mismatch (-want +got):\n%s", diff)
+		}
+	})
+}
+
+func Test_parseTestList(t *testing.T) {
+	t.Run("Parse the output of go test -list", func(t *testing.T) {
+		out := "TestA\nExampleA\nBenchmarkA\nok  \texample.com/a\t0.003s\n" +
+			"?   \texample.com/b\t[no test files]\n" +
+			"TestC\nFuzzC\nok  \texample.com/c\t0.002s\n"
+		got, err := parseTestList(strings.NewReader(out))
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := []shardUnit{
+			{pkg: "example.com/a", test: "TestA"},
+			{pkg: "example.com/a", test: "ExampleA"},
+			{pkg: "example.com/c", test: "TestC"},
+			{pkg: "example.com/c", test: "FuzzC"},
+		}
+		if diff := cmp.Diff(want, got, cmp.AllowUnexported(shardUnit{})); diff != "" {
+			t.Errorf("parseTestList() mismatch (-want +got):\n%s", diff)
+		}
+	})
+}
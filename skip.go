@@ -0,0 +1,93 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/nao1215/hottest/testjson"
+)
+
+// noSkipReason is the reason of the test that is skipped without a message, e.g. t.SkipNow().
+const noSkipReason = "no reason given"
+
+// skipGroup is the skipped tests that have the same reason.
+type skipGroup struct {
+	// reason is the message passed to t.Skip() without the location.
+	reason string
+	// tests is the skipped tests in the order of appearance.
+	tests []*testjson.TestResult
+}
+
+// skipReason returns the reason of the skipped test as go test prints it, e.g. "a_test.go:15: not supported".
+// It returns noSkipReason if the test is skipped without a message.
+func skipReason(t *testjson.TestResult) string {
+	if reason := t.SkipReason(); reason != "" {
+		return reason
+	}
+	return noSkipReason
+}
+
+// skipLocation returns the reason of the skipped test without the location and the location of t.Skip(),
+// e.g. "not supported" and "a_test.go:15". The location is empty if the reason does not have it.
+func skipLocation(t *testjson.TestResult) (string, string) {
+	reason := skipReason(t)
+	m := locationRegexp.FindStringSubmatchIndex(reason)
+	if m == nil {
+		return reason, ""
+	}
+	return reason[m[1]:], reason[m[2]:m[3]] + ":" + reason[m[4]:m[5]]
+}
+
+// groupSkippedTests groups the skipped tests by reason. The groups are sorted in descending order
+// of the number of tests, and the groups of the same number are in the order of appearance.
+func groupSkippedTests(tests []*testjson.TestResult) []*skipGroup {
+	groups := []*skipGroup{}
+	index := map[string]*skipGroup{}
+	for _, t := range tests {
+		reason, _ := skipLocation(t)
+		g, ok := index[reason]
+		if !ok {
+			g = &skipGroup{reason: reason}
+			index[reason] = g
+			groups = append(groups, g)
+		}
+		g.tests = append(g.tests, t)
+	}
+	sort.SliceStable(groups, func(i, j int) bool {
+		return len(groups[i].tests) > len(groups[j].tests)
+	})
+	return groups
+}
+
+// skippedTestName returns the name of the skipped test with the package and the location,
+// e.g. "TestX (example.com/a, a_test.go:15)".
+func skippedTestName(t *testjson.TestResult) string {
+	if _, loc := skipLocation(t); loc != "" {
+		return fmt.Sprintf("%s%s (%s, %s)", t.Label(), t.Name, t.Package, loc)
+	}
+	return fmt.Sprintf("%s%s (%s)", t.Label(), t.Name, t.Package)
+}
+
+// printSkipped prints the skipped tests with their reasons, e.g. "TestX (example.com/a): a_test.go:15: not supported".
+// If group is true, the tests are listed under each reason.
+func printSkipped(w io.Writer, r *testjson.Results, group bool) {
+	skipped := r.Tests(testjson.ActionSkip)
+	if len(skipped) == 0 {
+		return
+	}
+
+	fmt.Fprintf(w, "[Skipped Tests]\n")
+	if !group {
+		for _, t := range skipped {
+			fmt.Fprintf(w, " %s%s (%s): %s\n", t.Label(), t.Name, t.Package, skipReason(t))
+		}
+		return
+	}
+	for _, g := range groupSkippedTests(skipped) {
+		fmt.Fprintf(w, " %s (%d)\n", g.reason, len(g.tests))
+		for _, t := range g.tests {
+			fmt.Fprintf(w, "   %s\n", skippedTestName(t))
+		}
+	}
+}
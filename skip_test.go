@@ -0,0 +1,72 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func Test_skipLocation(t *testing.T) {
+	h := newHottestFromLog(t, "testdata/skip.json")
+
+	tests := []struct {
+		name         string
+		test         string
+		wantReason   string
+		wantLocation string
+	}{
+		{name: "Split the location of t.Skip", test: "TestDB", wantReason: "DATABASE_URL is not set", wantLocation: "skip_test.go:10"},
+		{name: "Split the location of the subtest", test: "TestSlow/short", wantReason: "skipped in short mode", wantLocation: "skip_test.go:23"},
+		{name: "The test skipped without a message has no location", test: "TestNoReason", wantReason: noSkipReason},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reason, loc := skipLocation(h.Results.Find("example.com/sample/skip", tt.test))
+			if reason != tt.wantReason || loc != tt.wantLocation {
+				t.Errorf("skipLocation() = %q, %q, want %q, %q", reason, loc, tt.wantReason, tt.wantLocation)
+			}
+		})
+	}
+}
+
+func Test_printSkipped(t *testing.T) {
+	h := newHottestFromLog(t, "testdata/skip.json")
+
+	t.Run("Print the skipped tests with their reasons", func(t *testing.T) {
+		var b strings.Builder
+		printSkipped(&b, h.Results, false)
+		want := "[Skipped Tests]\n" +
+			" TestDB (example.com/sample/skip): skip_test.go:10: DATABASE_URL is not set\n" +
+			" TestCache (example.com/sample/skip): skip_test.go:16: DATABASE_URL is not set\n" +
+			" TestSlow/short (example.com/sample/skip): skip_test.go:23: skipped in short mode\n" +
+			" TestNoReason (example.com/sample/skip): no reason given\n"
+		if diff := cmp.Diff(want, b.String()); diff != "" {
+			t.Errorf("printSkipped() mismatch (-want +got):\n%s", diff)
+		}
+	})
+
+	t.Run("Group the skipped tests by reason in descending order of the number of tests", func(t *testing.T) {
+		var b strings.Builder
+		printSkipped(&b, h.Results, true)
+		want := "[Skipped Tests]\n" +
+			" DATABASE_URL is not set (2)\n" +
+			"   TestDB (example.com/sample/skip, skip_test.go:10)\n" +
+			"   TestCache (example.com/sample/skip, skip_test.go:16)\n" +
+			" skipped in short mode (1)\n" +
+			"   TestSlow/short (example.com/sample/skip, skip_test.go:23)\n" +
+			" no reason given (1)\n" +
+			"   TestNoReason (example.com/sample/skip)\n"
+		if diff := cmp.Diff(want, b.String()); diff != "" {
+			t.Errorf("printSkipped() mismatch (-want +got):\n%s", diff)
+		}
+	})
+
+	t.Run("Print nothing if no tests are skipped", func(t *testing.T) {
+		var b strings.Builder
+		printSkipped(&b, newHottestFromLog(t, "testdata/example.json").Results, false)
+		if b.String() != "" {
+			t.Errorf("printSkipped() = %q, want empty", b.String())
+		}
+	})
+}
@@ -0,0 +1,228 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/nao1215/hottest/testjson"
+)
+
+const (
+	// teamCityUnfinishedMessage is the message of the test that did not finish, e.g. because the test binary timed out.
+	teamCityUnfinishedMessage = "the test did not finish"
+	// teamCityBuildFailedMessage is the message of the package that failed to build.
+	teamCityBuildFailedMessage = "build failed"
+	// teamCityPackageFailedMessage is the message of the package that failed without a failed test,
+	// e.g. TestMain exited with 1 or the test binary panicked before the tests started.
+	teamCityPackageFailedMessage = "package failed"
+)
+
+// teamCityWriter writes the test events as TeamCity service messages in real time.
+// Each package is reported as a test suite. The package name is used as flowId
+// because 'go test' runs the packages in parallel, and each test has its own flow
+// whose parent is the flow of the package because the tests run in parallel with t.Parallel().
+// If a package fails without a failed test (e.g. a build failure or a timeout), the tests that did
+// not finish are reported as failed, or the package is reported as a failed test with its output.
+// It is safe for concurrent use because the test commands may run in parallel.
+type teamCityWriter struct {
+	mu sync.Mutex
+	w  io.Writer
+	// packages is the state of the started packages keyed by the package name.
+	packages map[string]*teamCityPackage
+	// buildOutput is the build output keyed by the ImportPath of the build, e.g. "example.com/a [example.com/a.test]".
+	buildOutput map[string][]string
+}
+
+// teamCityPackage is the state of a started package that is needed to report its failure.
+type teamCityPackage struct {
+	// failed is true if a test of the package failed.
+	failed bool
+	// running is the output of the tests that started and have not finished, keyed by the test name.
+	running map[string][]string
+	// output is the output of the package that does not belong to any test.
+	output []string
+}
+
+// newTeamCityWriter returns a teamCityWriter that writes to w.
+func newTeamCityWriter(w io.Writer) *teamCityWriter {
+	return &teamCityWriter{w: w, packages: map[string]*teamCityPackage{}, buildOutput: map[string][]string{}}
+}
+
+// OnStart does nothing because the service messages are written for each package.
+func (t *teamCityWriter) OnStart() {}
+
+// OnEvent writes the service messages for the test event.
+func (t *teamCityWriter) OnEvent(o testjson.TestOutputJSON) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if o.Action == testjson.ActionBuildOutput {
+		t.buildOutput[o.ImportPath] = append(t.buildOutput[o.ImportPath], strings.TrimRight(o.Output, "\n"))
+		return
+	}
+	if o.Package == "" {
+		return
+	}
+
+	flowID := teamCityEscape(o.Package)
+	p, ok := t.packages[o.Package]
+	if !ok {
+		p = &teamCityPackage{running: map[string][]string{}}
+		t.packages[o.Package] = p
+		t.message("testSuiteStarted name='%s' flowId='%s'", flowID, flowID)
+	}
+
+	if o.Test == "" {
+		switch o.Action {
+		case testjson.ActionOutput:
+			p.output = append(p.output, strings.TrimRight(o.Output, "\n"))
+		case testjson.ActionPass, testjson.ActionFail, testjson.ActionSkip:
+			if o.Action == testjson.ActionFail {
+				output := append(append([]string{}, t.buildOutput[o.FailedBuild]...), p.output...)
+				t.failPackage(o.Package, p, output, o.FailedBuild != "")
+			}
+			delete(t.packages, o.Package)
+			t.message("testSuiteFinished name='%s' flowId='%s'", flowID, flowID)
+		}
+		return
+	}
+
+	name := teamCityEscape(o.Test)
+	testFlowID := teamCityTestFlowID(o.Package, o.Test)
+	switch o.Action {
+	case testjson.ActionRun:
+		p.running[o.Test] = []string{}
+		t.startTest(o.Package, o.Test)
+	case testjson.ActionOutput:
+		if output, ok := p.running[o.Test]; ok {
+			p.running[o.Test] = append(output, strings.TrimRight(o.Output, "\n"))
+		}
+		if testjson.IsRecordableErrorMessage(o.Output) {
+			t.message("testStdOut name='%s' out='%s' flowId='%s'", name, teamCityEscape(strings.TrimRight(o.Output, "\n")), testFlowID)
+		}
+	}
+}
+
+// OnTestFinished writes the service messages for the result of the test.
+func (t *teamCityWriter) OnTestFinished(r *testjson.TestResult) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if p, ok := t.packages[r.Package]; ok {
+		delete(p.running, r.Name)
+		if r.Action == testjson.ActionFail {
+			p.failed = true
+		}
+	}
+	t.finishTest(r)
+}
+
+// OnFinish does nothing because the service messages are written for each test.
+func (t *teamCityWriter) OnFinish(Summary) {}
+
+// startTest writes the service messages for the test that starts.
+func (t *teamCityWriter) startTest(pkg, test string) {
+	testFlowID := teamCityTestFlowID(pkg, test)
+	t.message("flowStarted flowId='%s' parent='%s'", testFlowID, teamCityEscape(pkg))
+	t.message("testStarted name='%s' flowId='%s'", teamCityEscape(test), testFlowID)
+}
+
+// finishTest writes the service messages for the result of the test.
+func (t *teamCityWriter) finishTest(r *testjson.TestResult) {
+	flowID := teamCityTestFlowID(r.Package, r.Name)
+	name := teamCityEscape(r.Name)
+	switch r.Action {
+	case testjson.ActionFail:
+		msgs := r.Messages()
+		t.message("testFailed name='%s' message='%s' details='%s' flowId='%s'",
+			name, teamCityEscape(failureSummary(msgs)), teamCityEscape(strings.Join(msgs, "\n")), flowID)
+	case testjson.ActionSkip:
+		t.message("testIgnored name='%s' message='%s' flowId='%s'", name, teamCityEscape(r.SkipReason()), flowID)
+	}
+	t.finished(name, flowID, r.Elapsed)
+	t.message("flowFinished flowId='%s'", flowID)
+}
+
+// failTest writes the service messages for the started test that failed with the message and the output.
+func (t *teamCityWriter) failTest(pkg, test, message string, output []string) {
+	flowID := teamCityTestFlowID(pkg, test)
+	name := teamCityEscape(test)
+	t.message("testFailed name='%s' message='%s' details='%s' flowId='%s'",
+		name, teamCityEscape(message), teamCityEscape(strings.Join(output, "\n")), flowID)
+	t.finished(name, flowID, 0)
+	t.message("flowFinished flowId='%s'", flowID)
+}
+
+// failPackage writes the service messages for the failed package. The running tests are reported as failed.
+// If no test failed, the package is reported as a failed test whose name is the package name,
+// so that TeamCity does not regard the suite as passed.
+func (t *teamCityWriter) failPackage(pkg string, p *teamCityPackage, output []string, buildFailed bool) {
+	running := make([]string, 0, len(p.running))
+	for name := range p.running {
+		running = append(running, name)
+	}
+	sort.Strings(running)
+	for _, name := range running {
+		t.failTest(pkg, name, teamCityUnfinishedMessage, p.running[name])
+		p.failed = true
+	}
+	if p.failed {
+		return
+	}
+
+	message := teamCityPackageFailedMessage
+	if buildFailed {
+		message = teamCityBuildFailedMessage
+	}
+	t.startTest(pkg, pkg)
+	t.failTest(pkg, pkg, message, output)
+}
+
+// writeTeamCityResults writes the service messages of the results after the run. It is used when the
+// progress is not printed in the teamcity format, e.g. -hottest.format=dots on TeamCity.
+func writeTeamCityResults(w io.Writer, r *testjson.Results) {
+	t := newTeamCityWriter(w)
+	for _, p := range r.Packages {
+		flowID := teamCityEscape(p.Name)
+		t.message("testSuiteStarted name='%s' flowId='%s'", flowID, flowID)
+		state := &teamCityPackage{running: map[string][]string{}}
+		for _, test := range p.Tests {
+			t.startTest(p.Name, test.Name)
+			if test.Action == "" {
+				state.running[test.Name] = test.Output
+				continue
+			}
+			if test.Action == testjson.ActionFail {
+				state.failed = true
+			}
+			t.finishTest(test)
+		}
+		if p.Action == testjson.ActionFail {
+			t.failPackage(p.Name, state, append(append([]string{}, r.BuildOutput(p)...), p.Output...), p.FailedBuild != "")
+		}
+		t.message("testSuiteFinished name='%s' flowId='%s'", flowID, flowID)
+	}
+}
+
+// finished writes the testFinished service message.
+func (t *teamCityWriter) finished(name, flowID string, elapsed float64) {
+	t.message("testFinished name='%s' duration='%d' flowId='%s'", name, int64(elapsed*1000), flowID)
+}
+
+// message writes a service message.
+func (t *teamCityWriter) message(format string, args ...interface{}) {
+	fmt.Fprintf(t.w, "##teamcity[%s]\n", fmt.Sprintf(format, args...))
+}
+
+// teamCityTestFlowID returns the escaped flowId of the test, e.g. "example.com/a.TestX/sub".
+func teamCityTestFlowID(pkg, test string) string {
+	return teamCityEscape(pkg + "." + test)
+}
+
+// teamCityEscape escapes the value of the TeamCity service message.
+func teamCityEscape(s string) string {
+	return strings.NewReplacer("|", "||", "'", "|'", "\n", "|n", "\r", "|r", "[", "|[", "]", "|]").Replace(s)
+}
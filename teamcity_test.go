@@ -0,0 +1,216 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"os"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/nao1215/hottest/testjson"
+)
+
+func Test_teamCityWriter(t *testing.T) {
+	t.Run("Write service messages as the events arrive", func(t *testing.T) {
+		f, err := os.Open("testdata/sample.json")
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer f.Close() //nolint
+
+		var b bytes.Buffer
+		h := &hottest{
+			opts:       &options{format: formatTeamCity},
+			reporters:  []Reporter{newTeamCityWriter(&b)},
+			Aggregator: testjson.NewAggregator(),
+		}
+		scanner := bufio.NewScanner(f)
+		for scanner.Scan() {
+			if err := h.parse(scanner.Text()); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		flow := "flowId='example.com/sample/a'"
+		pass := "flowId='example.com/sample/a.TestPass'"
+		fail := "flowId='example.com/sample/a.TestFail'"
+		subOK := "flowId='example.com/sample/a.TestFail/sub_ok'"
+		subNG := "flowId='example.com/sample/a.TestFail/sub_ng'"
+		skip := "flowId='example.com/sample/a.TestSkip'"
+		parent := "parent='example.com/sample/a'"
+		want := []string{
+			"##teamcity[testSuiteStarted name='example.com/sample/a' " + flow + "]",
+			"##teamcity[flowStarted " + pass + " " + parent + "]",
+			"##teamcity[testStarted name='TestPass' " + pass + "]",
+			"##teamcity[testFinished name='TestPass' duration='0' " + pass + "]",
+			"##teamcity[flowFinished " + pass + "]",
+			"##teamcity[flowStarted " + fail + " " + parent + "]",
+			"##teamcity[testStarted name='TestFail' " + fail + "]",
+			"##teamcity[flowStarted " + subOK + " " + parent + "]",
+			"##teamcity[testStarted name='TestFail/sub_ok' " + subOK + "]",
+			"##teamcity[flowStarted " + subNG + " " + parent + "]",
+			"##teamcity[testStarted name='TestFail/sub_ng' " + subNG + "]",
+			"##teamcity[testStdOut name='TestFail/sub_ng' out='    a_test.go:10: got 1, want 2' " + subNG + "]",
+			"##teamcity[testFinished name='TestFail/sub_ok' duration='0' " + subOK + "]",
+			"##teamcity[flowFinished " + subOK + "]",
+			"##teamcity[testFailed name='TestFail/sub_ng' message='a_test.go:10: got 1, want 2' details='    a_test.go:10: got 1, want 2' " + subNG + "]",
+			"##teamcity[testFinished name='TestFail/sub_ng' duration='0' " + subNG + "]",
+			"##teamcity[flowFinished " + subNG + "]",
+			"##teamcity[testFailed name='TestFail' message='Failed' details='' " + fail + "]",
+			"##teamcity[testFinished name='TestFail' duration='0' " + fail + "]",
+			"##teamcity[flowFinished " + fail + "]",
+			"##teamcity[flowStarted " + skip + " " + parent + "]",
+			"##teamcity[testStarted name='TestSkip' " + skip + "]",
+			"##teamcity[testStdOut name='TestSkip' out='    a_test.go:15: not supported on this platform' " + skip + "]",
+			"##teamcity[testIgnored name='TestSkip' message='a_test.go:15: not supported on this platform' " + skip + "]",
+			"##teamcity[testFinished name='TestSkip' duration='0' " + skip + "]",
+			"##teamcity[flowFinished " + skip + "]",
+			"##teamcity[testSuiteFinished name='example.com/sample/a' " + flow + "]",
+		}
+		got := []string{}
+		for _, l := range bytes.Split(b.Bytes(), []byte("\n")) {
+			if bytes.Contains(l, []byte("example.com/sample/a")) {
+				got = append(got, string(l))
+			}
+		}
+		if diff := cmp.Diff(want, got); diff != "" {
+			t.Errorf("service messages mismatch (-want +got):\n%s", diff)
+		}
+	})
+
+	t.Run("Write each parallel test in its own flow", func(t *testing.T) {
+		f, err := os.Open("testdata/parallel.json")
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer f.Close() //nolint
+
+		var b bytes.Buffer
+		h := &hottest{
+			opts:       &options{format: formatTeamCity},
+			reporters:  []Reporter{newTeamCityWriter(&b)},
+			Aggregator: testjson.NewAggregator(),
+		}
+		scanner := bufio.NewScanner(f)
+		for scanner.Scan() {
+			if err := h.parse(scanner.Text()); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		flow := "flowId='example.com/sample/parallel'"
+		a := "flowId='example.com/sample/parallel.TestA'"
+		bFlow := "flowId='example.com/sample/parallel.TestB'"
+		parent := "parent='example.com/sample/parallel'"
+		want := []string{
+			"##teamcity[testSuiteStarted name='example.com/sample/parallel' " + flow + "]",
+			"##teamcity[flowStarted " + a + " " + parent + "]",
+			"##teamcity[testStarted name='TestA' " + a + "]",
+			"##teamcity[flowStarted " + bFlow + " " + parent + "]",
+			"##teamcity[testStarted name='TestB' " + bFlow + "]",
+			"##teamcity[testStdOut name='TestA' out='    parallel_test.go:11: a failed' " + a + "]",
+			"##teamcity[testStdOut name='TestB' out='    parallel_test.go:18: b failed' " + bFlow + "]",
+			"##teamcity[testFailed name='TestB' message='parallel_test.go:18: b failed' details='    parallel_test.go:18: b failed' " + bFlow + "]",
+			"##teamcity[testFinished name='TestB' duration='40' " + bFlow + "]",
+			"##teamcity[flowFinished " + bFlow + "]",
+			"##teamcity[testFailed name='TestA' message='parallel_test.go:11: a failed' details='    parallel_test.go:11: a failed' " + a + "]",
+			"##teamcity[testFinished name='TestA' duration='60' " + a + "]",
+			"##teamcity[flowFinished " + a + "]",
+			"##teamcity[testSuiteFinished name='example.com/sample/parallel' " + flow + "]",
+		}
+		got := strings.Split(strings.TrimSpace(b.String()), "\n")
+		if diff := cmp.Diff(want, got); diff != "" {
+			t.Errorf("service messages mismatch (-want +got):\n%s", diff)
+		}
+	})
+
+	t.Run("Report the package that failed to build as a failed test", func(t *testing.T) {
+		got := teamCityMessagesFromLog(t, "testdata/build.json")
+		flow := "flowId='example.com/x/bad'"
+		bad := "flowId='example.com/x/bad.example.com/x/bad'"
+		details := "# example.com/x/bad |[example.com/x/bad.test|]|nbad/bad.go:3:12: undefined: undefined|nFAIL\texample.com/x/bad |[build failed|]"
+		want := []string{
+			"##teamcity[testSuiteStarted name='example.com/x/bad' " + flow + "]",
+			"##teamcity[flowStarted " + bad + " parent='example.com/x/bad']",
+			"##teamcity[testStarted name='example.com/x/bad' " + bad + "]",
+			"##teamcity[testFailed name='example.com/x/bad' message='build failed' details='" + details + "' " + bad + "]",
+			"##teamcity[testFinished name='example.com/x/bad' duration='0' " + bad + "]",
+			"##teamcity[flowFinished " + bad + "]",
+			"##teamcity[testSuiteFinished name='example.com/x/bad' " + flow + "]",
+		}
+		if diff := cmp.Diff(want, got); diff != "" {
+			t.Errorf("service messages mismatch (-want +got):\n%s", diff)
+		}
+	})
+
+	t.Run("Report the tests that did not finish as failed", func(t *testing.T) {
+		got := teamCityMessagesFromLog(t, "testdata/timeout.json")
+		slow := "flowId='example.com/x/slow.TestSlow'"
+		prefix := "##teamcity[testFailed name='TestSlow' message='the test did not finish' details='=== RUN   TestSlow|npanic: test timed out after 1s|n"
+		if len(got) < 4 || !strings.HasPrefix(got[len(got)-4], prefix) {
+			t.Fatalf("the unfinished test is not failed:\n%s", strings.Join(got, "\n"))
+		}
+		want := []string{
+			"##teamcity[testFinished name='TestSlow' duration='0' " + slow + "]",
+			"##teamcity[flowFinished " + slow + "]",
+			"##teamcity[testSuiteFinished name='example.com/x/slow' flowId='example.com/x/slow']",
+		}
+		if diff := cmp.Diff(want, got[len(got)-3:]); diff != "" {
+			t.Errorf("service messages mismatch (-want +got):\n%s", diff)
+		}
+	})
+
+	t.Run("Write the same service messages from the results after the run", func(t *testing.T) {
+		for _, path := range []string{"testdata/sample.json", "testdata/parallel.json", "testdata/build.json", "testdata/timeout.json"} {
+			want := []string{}
+			for _, m := range teamCityMessagesFromLog(t, path) {
+				if !strings.HasPrefix(m, "##teamcity[testStdOut ") {
+					want = append(want, m)
+				}
+			}
+			var b bytes.Buffer
+			writeTeamCityResults(&b, newHottestFromLog(t, path).Results)
+			got := strings.Split(strings.TrimSpace(b.String()), "\n")
+
+			sort.Strings(want)
+			sort.Strings(got)
+			if diff := cmp.Diff(want, got); diff != "" {
+				t.Errorf("%s: service messages mismatch (-want +got):\n%s", path, diff)
+			}
+		}
+	})
+
+	t.Run("Escape special characters", func(t *testing.T) {
+		got := teamCityEscape("it's [a]\n|b|")
+		if diff := cmp.Diff("it|'s |[a|]|n||b||", got); diff != "" {
+			t.Errorf("teamCityEscape() mismatch (-want +got):\n%s", diff)
+		}
+	})
+}
+
+// teamCityMessagesFromLog returns the service messages that teamCityWriter writes for the log.
+func teamCityMessagesFromLog(t *testing.T, path string) []string {
+	t.Helper()
+
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close() //nolint
+
+	var b bytes.Buffer
+	h := &hottest{
+		opts:       &options{format: formatTeamCity},
+		reporters:  []Reporter{newTeamCityWriter(&b)},
+		Aggregator: testjson.NewAggregator(),
+	}
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		if err := h.parse(scanner.Text()); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return strings.Split(strings.TrimSpace(b.String()), "\n")
+}
@@ -0,0 +1,16 @@
+package a
+
+import "testing"
+
+func TestPass(t *testing.T) {}
+
+func TestFail(t *testing.T) {
+	t.Run("sub_ok", func(t *testing.T) {})
+	t.Run("sub_ng", func(t *testing.T) {
+		t.Errorf("got 1, want 2")
+	})
+}
+
+func TestSkip(t *testing.T) {
+	t.Skip("not supported on this platform")
+}
@@ -0,0 +1,150 @@
+package testjson
+
+import (
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/tenntenn/testtime"
+)
+
+// TestStats holds the test statistics.
+type TestStats struct {
+	// Pass is the number of passed tests.
+	Pass int32 `json:"pass"`
+	// Fail is the number of failed tests.
+	Fail int32 `json:"fail"`
+	// Skip is the number of skipped tests.
+	Skip int32 `json:"skip"`
+	// Total is the number of total tests.
+	Total int32 `json:"total"`
+}
+
+// Aggregator aggregates the events of 'go test -json' into the test statistics and the results.
+// It is not safe for concurrent use. To aggregate multiple outputs concurrently,
+// use an Aggregator for each output and merge them with Merge after the outputs end.
+type Aggregator struct {
+	// Stats is the statistics of the tests, which is counted from the "--- PASS",
+	// "--- FAIL" and "--- SKIP" lines.
+	Stats TestStats
+	// Messages is the output lines of the tests, which are used to extract the error messages.
+	Messages []string
+	// Results is the per-package and per-test results.
+	Results *Results
+
+	// failMessages is the error messages that are extracted from the merged aggregators.
+	failMessages []string
+}
+
+// NewAggregator returns an empty Aggregator.
+func NewAggregator() *Aggregator {
+	return &Aggregator{
+		Stats:    TestStats{},
+		Messages: []string{},
+		Results:  NewResults(),
+	}
+}
+
+// Add updates the test statistics, the output and the results with the event.
+func (a *Aggregator) Add(outputJSON TestOutputJSON) {
+	a.Results.Record(outputJSON)
+	trimmed := strings.TrimSpace(outputJSON.Output)
+
+	switch {
+	case strings.HasPrefix(trimmed, "ok"):
+		fallthrough
+	case strings.HasPrefix(trimmed, "FAIL"):
+		fallthrough
+	case strings.HasPrefix(trimmed, "PASS"):
+		fallthrough
+	case strings.Contains(trimmed, "[no test files]"):
+		return
+
+	case strings.HasPrefix(trimmed, "=== RUN"):
+		fallthrough
+	case strings.HasPrefix(trimmed, "=== CONT"):
+		fallthrough
+	case strings.HasPrefix(trimmed, "=== PAUSE"):
+		a.Messages = append(a.Messages, strings.TrimRightFunc(outputJSON.Output, unicode.IsSpace))
+		return
+
+	// passed
+	case strings.HasPrefix(trimmed, "--- PASS"):
+		a.Stats.Pass++
+		a.Stats.Total++
+		a.Messages = append(a.Messages, strings.TrimRightFunc(outputJSON.Output, unicode.IsSpace))
+
+	// skipped
+	case strings.HasPrefix(trimmed, "--- SKIP"):
+		a.Stats.Skip++
+		a.Stats.Total++
+		a.Messages = append(a.Messages, strings.TrimRightFunc(outputJSON.Output, unicode.IsSpace))
+
+	// failed
+	case strings.HasPrefix(trimmed, "--- FAIL"):
+		a.Stats.Fail++
+		a.Stats.Total++
+		a.Messages = append(a.Messages, strings.TrimRightFunc(outputJSON.Output, unicode.IsSpace))
+
+	default:
+		a.Messages = append(a.Messages, strings.TrimRightFunc(outputJSON.Output, unicode.IsSpace))
+	}
+}
+
+// FailMessages returns the error messages of the failed tests.
+func (a *Aggregator) FailMessages() []string {
+	if a.failMessages != nil {
+		return a.failMessages
+	}
+	return ExtractFailTestMessage(a.Messages)
+}
+
+// Merge adds the statistics, the results and the error messages of other that are labeled with the source.
+// The error messages are extracted from the output of other, and the top-level failures are prefixed
+// with the label, e.g. "[linux] --- FAIL: TestX". If the label is empty, the results are added without the source.
+// After Merge, FailMessages returns the merged error messages instead of extracting them from Messages.
+func (a *Aggregator) Merge(label string, other *Aggregator) {
+	if a.failMessages == nil {
+		a.failMessages = ExtractFailTestMessage(a.Messages)
+	}
+	a.Stats.Pass += other.Stats.Pass
+	a.Stats.Fail += other.Stats.Fail
+	a.Stats.Skip += other.Stats.Skip
+	a.Stats.Total += other.Stats.Total
+	a.Results.Add(label, other.Results)
+	for _, msg := range other.FailMessages() {
+		if label != "" && strings.HasPrefix(msg, "--- FAIL") {
+			msg = "[" + label + "] " + msg
+		}
+		a.failMessages = append(a.failMessages, msg)
+	}
+}
+
+// Interval represents a time interval.
+type Interval struct {
+	// Started is the Started time of the interval.
+	Started time.Time
+	// Finished is the Finished time of the interval.
+	Finished time.Time
+}
+
+// NewInterval creates a new interval.
+// This method is not set the start and end time of the interval.
+func NewInterval() *Interval {
+	return &Interval{}
+}
+
+// Start sets the start time of the interval.
+func (i *Interval) Start() {
+	i.Started = testtime.Now()
+}
+
+// End sets the end time of the interval.
+func (i *Interval) End() {
+	i.Finished = testtime.Now()
+}
+
+// Duration returns the duration of the interval.
+func (i Interval) Duration() time.Duration {
+	return i.Finished.Sub(i.Started)
+}
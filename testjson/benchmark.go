@@ -0,0 +1,147 @@
+package testjson
+
+import (
+	"strconv"
+	"strings"
+)
+
+// Standard units of the benchmark results.
+const (
+	// UnitNsPerOp is the unit of the nanoseconds per operation.
+	UnitNsPerOp = "ns/op"
+	// UnitBytesPerOp is the unit of the allocated bytes per operation.
+	UnitBytesPerOp = "B/op"
+	// UnitAllocsPerOp is the unit of the allocations per operation.
+	UnitAllocsPerOp = "allocs/op"
+)
+
+// Benchmark holds the result of a benchmark line,
+// e.g. "BenchmarkX-8   1000   1234 ns/op   56 B/op   2 allocs/op".
+type Benchmark struct {
+	// Source is the label of the log that the benchmark result is read from.
+	// It is empty unless the results of multiple logs are merged.
+	Source string `json:"source,omitempty"`
+	// Package is the package name of the benchmark.
+	Package string `json:"package"`
+	// Name is the benchmark name without the GOMAXPROCS suffix, e.g. "BenchmarkX/size=10".
+	Name string `json:"name"`
+	// Procs is the value of GOMAXPROCS, which is the "-8" suffix of the name.
+	Procs int `json:"procs"`
+	// Iterations is the number of iterations (b.N).
+	Iterations int64 `json:"iterations"`
+	// NsPerOp is the nanoseconds per operation.
+	NsPerOp float64 `json:"ns_per_op"`
+	// HasMemory is true if the allocations are reported by -benchmem or b.ReportAllocs.
+	HasMemory bool `json:"has_memory"`
+	// BytesPerOp is the allocated bytes per operation.
+	BytesPerOp int64 `json:"bytes_per_op"`
+	// AllocsPerOp is the allocations per operation.
+	AllocsPerOp int64 `json:"allocs_per_op"`
+	// Metrics is the other metrics by unit, such as "MB/s" (b.SetBytes) and the metrics of b.ReportMetric.
+	Metrics map[string]float64 `json:"metrics,omitempty"`
+}
+
+// ParseBenchmark parses a benchmark result line. It returns false if the line is not a benchmark result.
+func ParseBenchmark(line string) (*Benchmark, bool) {
+	fields := strings.Fields(line)
+	if len(fields) < 4 || len(fields)%2 != 0 || !strings.HasPrefix(fields[0], "Benchmark") {
+		return nil, false
+	}
+	iterations, err := strconv.ParseInt(fields[1], 10, 64)
+	if err != nil {
+		return nil, false
+	}
+
+	b := &Benchmark{Iterations: iterations}
+	b.Name, b.Procs = splitProcs(fields[0])
+	for i := 2; i < len(fields); i += 2 {
+		value, err := strconv.ParseFloat(fields[i], 64)
+		if err != nil {
+			return nil, false
+		}
+		switch unit := fields[i+1]; unit {
+		case UnitNsPerOp:
+			b.NsPerOp = value
+		case UnitBytesPerOp:
+			b.HasMemory = true
+			b.BytesPerOp = int64(value)
+		case UnitAllocsPerOp:
+			b.HasMemory = true
+			b.AllocsPerOp = int64(value)
+		default:
+			if b.Metrics == nil {
+				b.Metrics = map[string]float64{}
+			}
+			b.Metrics[unit] = value
+		}
+	}
+	return b, true
+}
+
+// splitProcs splits the benchmark name into the name and GOMAXPROCS, e.g. "BenchmarkX-8" into "BenchmarkX" and 8.
+// If the name does not have the suffix, GOMAXPROCS is 1. go test never prints "-1" as the suffix,
+// so it is a part of the name, e.g. "BenchmarkX/n=-1".
+func splitProcs(name string) (string, int) {
+	i := strings.LastIndex(name, "-")
+	if i < 0 {
+		return name, 1
+	}
+	procs, err := strconv.Atoi(name[i+1:])
+	if err != nil || procs <= 1 {
+		return name, 1
+	}
+	return name[:i], procs
+}
+
+// FullName returns the name with the GOMAXPROCS suffix that go test prints, e.g. "BenchmarkX-8".
+func (b *Benchmark) FullName() string {
+	if b.Procs == 1 {
+		return b.Name
+	}
+	return b.Name + "-" + strconv.Itoa(b.Procs)
+}
+
+// Label returns the source label of the benchmark, e.g. "[linux] ".
+// It returns an empty string if the benchmark does not have a source.
+func (b *Benchmark) Label() string {
+	if b.Source == "" {
+		return ""
+	}
+	return "[" + b.Source + "] "
+}
+
+// Benchmarks returns all benchmark results in the order of appearance.
+func (r *Results) Benchmarks() []*Benchmark {
+	benchmarks := []*Benchmark{}
+	for _, p := range r.Packages {
+		benchmarks = append(benchmarks, p.Benchmarks...)
+	}
+	return benchmarks
+}
+
+// recordBenchmark records the benchmark result in the output of the test or the package.
+// The line is detected by its prefix rather than the test name because test2json may attribute
+// the benchmark output to the test that ran before, and older Go versions print it as the output
+// of the package. go test may print a result line in multiple events, e.g. "BenchmarkX-8 \t" and
+// "1000\t1234 ns/op\n", so the output is buffered until the line ends.
+func (r *Results) recordBenchmark(pkg *PackageResult, test, output string) {
+	key := pkg.Key() + " " + test
+	line, buffered := r.partialLines[key]
+	if !buffered && !strings.HasPrefix(output, "Benchmark") {
+		return
+	}
+	line += output
+	if !strings.HasSuffix(line, "\n") {
+		r.partialLines[key] = line
+		return
+	}
+	delete(r.partialLines, key)
+
+	b, ok := ParseBenchmark(line)
+	if !ok {
+		return
+	}
+	b.Source = pkg.Source
+	b.Package = pkg.Name
+	pkg.Benchmarks = append(pkg.Benchmarks, b)
+}
@@ -0,0 +1,90 @@
+package testjson
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestParseBenchmark(t *testing.T) {
+	tests := []struct {
+		name   string
+		line   string
+		want   *Benchmark
+		wantOK bool
+	}{
+		{
+			name: "Parse the result with the memory allocations",
+			line: "BenchmarkX-8   \t    1000\t      1234 ns/op\t      56 B/op\t       2 allocs/op\n",
+			want: &Benchmark{
+				Name: "BenchmarkX", Procs: 8, Iterations: 1000, NsPerOp: 1234,
+				HasMemory: true, BytesPerOp: 56, AllocsPerOp: 2,
+			},
+			wantOK: true,
+		},
+		{
+			name: "Parse the custom metrics of the sub-benchmark without GOMAXPROCS suffix",
+			line: "BenchmarkX/size=-1 \t 100\t 3.55 ns/op\t 120.5 MB/s\t 3.5 items/op",
+			want: &Benchmark{
+				Name: "BenchmarkX/size=-1", Procs: 1, Iterations: 100, NsPerOp: 3.55,
+				Metrics: map[string]float64{"MB/s": 120.5, "items/op": 3.5},
+			},
+			wantOK: true,
+		},
+		{
+			name:   "The name line printed before the result is not a result",
+			line:   "BenchmarkX\n",
+			wantOK: false,
+		},
+		{
+			name:   "The log of the benchmark is not a result",
+			line:   "BenchmarkX is slow: 10 times",
+			wantOK: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := ParseBenchmark(tt.line)
+			if ok != tt.wantOK {
+				t.Fatalf("ParseBenchmark() ok = %v, want %v", ok, tt.wantOK)
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("ParseBenchmark() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestResults_Benchmarks(t *testing.T) {
+	t.Run("Record the results printed in multiple events and attributed to a test", func(t *testing.T) {
+		agg := aggregateLog(t, "../testdata/bench.json")
+
+		got := []string{}
+		for _, b := range agg.Results.Benchmarks() {
+			got = append(got, b.Package+" "+b.FullName())
+		}
+		want := []string{
+			"example.com/bench BenchmarkSum",
+			"example.com/bench BenchmarkSum-2",
+			"example.com/bench BenchmarkSub/n=1",
+			"example.com/bench BenchmarkSub/n=1-2",
+		}
+		if diff := cmp.Diff(want, got); diff != "" {
+			t.Errorf("Benchmarks() mismatch (-want +got):\n%s", diff)
+		}
+		if b := agg.Results.Benchmarks()[2]; b.NsPerOp != 12.87 || b.Metrics["items/op"] != 3.5 || b.HasMemory {
+			t.Errorf("unexpected benchmark: %+v", b)
+		}
+		if diff := cmp.Diff(TestStats{Pass: 2, Total: 2}, agg.Stats); diff != "" {
+			t.Errorf("stats mismatch (-want +got):\n%s", diff)
+		}
+	})
+
+	t.Run("Label the benchmarks of the merged results", func(t *testing.T) {
+		r := NewResults()
+		r.Add("linux", aggregateLog(t, "../testdata/bench.json").Results)
+		if got := r.Benchmarks()[0].Label(); got != "[linux] " {
+			t.Errorf("Label() = %q, want %q", got, "[linux] ")
+		}
+	})
+}
@@ -0,0 +1,135 @@
+// Package testjson parses the output of 'go test -json'.
+//
+// The output is processed by a pipeline: Decode reads the lines and decodes them
+// into events in goroutines, and an Aggregator builds the test statistics and the
+// result tree (packages and tests) from the events. ExtractFailTestMessage extracts
+// the error messages of the failed tests from the output in the format that hottest prints.
+//
+//	agg := NewAggregator()
+//	for e := range Decode(r) {
+//		if e.Err != nil {
+//			continue
+//		}
+//		agg.Add(e.Output)
+//	}
+//	for _, msg := range agg.FailMessages() {
+//		fmt.Println(msg)
+//	}
+package testjson
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+const (
+	// ActionPass is the action of the passed test.
+	ActionPass = "pass"
+	// ActionFail is the action of the failed test.
+	ActionFail = "fail"
+	// ActionSkip is the action of the skipped test.
+	ActionSkip = "skip"
+	// ActionRun is the action of the started test.
+	ActionRun = "run"
+	// ActionOutput is the action of the test output.
+	ActionOutput = "output"
+	// ActionBuildOutput is the action of the output of the build, e.g. a compile error.
+	// The event has ImportPath instead of Package.
+	ActionBuildOutput = "build-output"
+)
+
+// TestOutputJSON represents the structure of a test output log entry.
+type TestOutputJSON struct {
+	Time    time.Time `json:"Time"`
+	Action  string    `json:"Action"`
+	Package string    `json:"Package"`
+	Test    string    `json:"Test"`
+	Output  string    `json:"Output,omitempty"`
+	Elapsed float64   `json:"Elapsed,omitempty"`
+	// ImportPath is the package of the build output, e.g. "example.com/a [example.com/a.test]".
+	ImportPath string `json:"ImportPath,omitempty"`
+	// FailedBuild is the ImportPath of the build that failed. It is set to the "fail" event of the package.
+	FailedBuild string `json:"FailedBuild,omitempty"`
+}
+
+// Event is a line of the 'go test -json' output decoded by Decode.
+type Event struct {
+	// Line is the raw line without the trailing newline.
+	Line string
+	// Output is the decoded test event. It is valid only if Err is nil.
+	Output TestOutputJSON
+	// Err is the error that occurs when the line is not a JSON or the output cannot be read.
+	// If the line is not a JSON, the error message is the line itself because it is likely
+	// to be an error message of 'go test', e.g. "package test is not in std".
+	Err error
+}
+
+// Decode reads the 'go test -json' output from r and returns the channel of the decoded events.
+// The lines are read by a reader goroutine and decoded by a decoder goroutine.
+// The channel is closed at the end of the stream, so the caller knows that all events
+// have been received when the channel is closed. If r cannot be read, an event with Err
+// is sent before the channel is closed. The caller must receive all events.
+func Decode(r io.Reader) <-chan Event {
+	return decodeEvents(readLines(r))
+}
+
+// DecodeLine decodes a line of the 'go test -json' output.
+func DecodeLine(line string) Event {
+	var o TestOutputJSON
+	if err := json.Unmarshal([]byte(line), &o); err != nil {
+		return Event{Line: line, Err: errors.New(line)}
+	}
+	return Event{Line: line, Output: o}
+}
+
+// line is a line read by the reader goroutine. err is set if r cannot be read.
+type line struct {
+	text string
+	err  error
+}
+
+// readLines reads the lines from r in a goroutine.
+// The channel is closed at the end of the stream or after an error is sent.
+func readLines(r io.Reader) <-chan line {
+	lines := make(chan line)
+	go func() {
+		defer close(lines)
+		reader := bufio.NewReader(r)
+		for {
+			l, err := reader.ReadString('\n')
+			if l = strings.TrimRight(l, "\r\n"); l != "" || err == nil {
+				lines <- line{text: l}
+			}
+			if errors.Is(err, io.EOF) {
+				return
+			}
+			if err != nil {
+				lines <- line{err: fmt.Errorf("failed to read test output: %w", err)}
+				return
+			}
+		}
+	}()
+	return lines
+}
+
+// decodeEvents decodes the lines into the events in a goroutine.
+// The channel is closed after the lines channel is closed.
+func decodeEvents(lines <-chan line) <-chan Event {
+	events := make(chan Event)
+	go func() {
+		defer close(events)
+		for l := range lines {
+			if l.err != nil {
+				events <- Event{Err: l.err}
+				continue
+			}
+			events <- DecodeLine(l.text)
+		}
+	}()
+	return events
+}
@@ -0,0 +1,41 @@
+package testjson
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestDecode(t *testing.T) {
+	t.Run("Read all lines including the last line without newline", func(t *testing.T) {
+		long := strings.Repeat("x", 100*1024)
+		got := []string{}
+		for e := range Decode(strings.NewReader("a\r\n" + long + "\nb")) {
+			got = append(got, e.Line)
+		}
+		if diff := cmp.Diff([]string{"a", long, "b"}, got); diff != "" {
+			t.Errorf("Decode() mismatch (-want +got):\n%s", diff)
+		}
+	})
+}
+
+func TestDecodeLine(t *testing.T) {
+	t.Run("Decode a test event", func(t *testing.T) {
+		e := DecodeLine(`{"Action":"pass","Package":"a","Test":"TestA","Elapsed":0.5}`)
+		if e.Err != nil {
+			t.Fatal(e.Err)
+		}
+		want := TestOutputJSON{Action: ActionPass, Package: "a", Test: "TestA", Elapsed: 0.5}
+		if diff := cmp.Diff(want, e.Output); diff != "" {
+			t.Errorf("DecodeLine() mismatch (-want +got):\n%s", diff)
+		}
+	})
+
+	t.Run("If the line is not a JSON, return the line as error", func(t *testing.T) {
+		e := DecodeLine("package test is not in std")
+		if e.Err == nil || e.Err.Error() != "package test is not in std" {
+			t.Errorf("DecodeLine() error = %v", e.Err)
+		}
+	})
+}
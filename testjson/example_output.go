@@ -0,0 +1,45 @@
+package testjson
+
+import (
+	"strings"
+	"unicode"
+)
+
+// IsExample returns true if the test is an example function, e.g. "ExampleInterval_Duration".
+func IsExample(name string) bool {
+	return strings.HasPrefix(name, "Example")
+}
+
+// ParseExampleOutput returns the actual and the expected output of a failed example function.
+// go test prints them after the "--- FAIL" line as "got:\n<output>\nwant:\n<output>\n".
+// The lines may be indented by the same spaces as the "got:" line, e.g. the error messages
+// extracted by ExtractFailTestMessage. It returns false if the lines do not contain the output.
+func ParseExampleOutput(lines []string) (got, want []string, ok bool) {
+	gotPos, wantPos := -1, -1
+	for i, v := range lines {
+		switch strings.TrimSpace(v) {
+		case "got:":
+			if gotPos < 0 {
+				gotPos = i
+			}
+		case "want:":
+			// The actual output may contain "want:", so the last one is used.
+			if gotPos >= 0 {
+				wantPos = i
+			}
+		}
+	}
+	if gotPos < 0 || wantPos < 0 {
+		return nil, nil, false
+	}
+
+	indent := lines[gotPos][:len(lines[gotPos])-len(strings.TrimLeftFunc(lines[gotPos], unicode.IsSpace))]
+	unindent := func(lines []string) []string {
+		out := make([]string, 0, len(lines))
+		for _, v := range lines {
+			out = append(out, strings.TrimPrefix(v, indent))
+		}
+		return out
+	}
+	return unindent(lines[gotPos+1 : wantPos]), unindent(lines[wantPos+1:]), true
+}
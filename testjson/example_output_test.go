@@ -0,0 +1,44 @@
+package testjson
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestParseExampleOutput(t *testing.T) {
+	t.Run("Parse the output of the failed example", func(t *testing.T) {
+		agg := aggregateLog(t, "../testdata/example.json")
+		example := agg.Results.Find("example.com/sample/example", "Example_hello")
+
+		got, want, ok := ParseExampleOutput(example.Output)
+		if !ok {
+			t.Fatal("ParseExampleOutput() should find the output")
+		}
+		if diff := cmp.Diff([]string{"hello", "wrld", "!"}, got); diff != "" {
+			t.Errorf("got mismatch (-want +got):\n%s", diff)
+		}
+		if diff := cmp.Diff([]string{"hello", "world", "!"}, want); diff != "" {
+			t.Errorf("want mismatch (-want +got):\n%s", diff)
+		}
+	})
+
+	t.Run("Remove the indent of the got line and keep the blank lines", func(t *testing.T) {
+		got, want, ok := ParseExampleOutput([]string{"    got:", "    a", "", "    want:", "    want:", "      b"})
+		if !ok {
+			t.Fatal("ParseExampleOutput() should find the output")
+		}
+		if diff := cmp.Diff([]string{"a", "", "want:"}, got); diff != "" {
+			t.Errorf("got mismatch (-want +got):\n%s", diff)
+		}
+		if diff := cmp.Diff([]string{"  b"}, want); diff != "" {
+			t.Errorf("want mismatch (-want +got):\n%s", diff)
+		}
+	})
+
+	t.Run("If there is no output, return false", func(t *testing.T) {
+		if _, _, ok := ParseExampleOutput([]string{"    a_test.go:10: got 1, want 2"}); ok {
+			t.Error("ParseExampleOutput() should return false")
+		}
+	})
+}
@@ -0,0 +1,38 @@
+package testjson_test
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/nao1215/hottest/testjson"
+)
+
+func ExampleAggregator() {
+	output := `{"Action":"run","Package":"example.com/a","Test":"TestA"}
+{"Action":"output","Package":"example.com/a","Test":"TestA","Output":"=== RUN   TestA\n"}
+{"Action":"output","Package":"example.com/a","Test":"TestA","Output":"    a_test.go:10: got 1, want 2\n"}
+{"Action":"output","Package":"example.com/a","Test":"TestA","Output":"--- FAIL: TestA (0.00s)\n"}
+{"Action":"fail","Package":"example.com/a","Test":"TestA","Elapsed":0}
+{"Action":"fail","Package":"example.com/a","Elapsed":0.1}
+`
+	agg := testjson.NewAggregator()
+	for e := range testjson.Decode(strings.NewReader(output)) {
+		if e.Err != nil {
+			continue
+		}
+		agg.Add(e.Output)
+	}
+
+	fmt.Printf("pass=%d fail=%d\n", agg.Stats.Pass, agg.Stats.Fail)
+	for _, msg := range agg.FailMessages() {
+		fmt.Println(msg)
+	}
+	for _, t := range agg.Results.Failures() {
+		fmt.Println(t.Package, t.Name, t.Messages())
+	}
+	// Output:
+	// pass=0 fail=1
+	// --- FAIL: TestA (0.00s)
+	//         a_test.go:10: got 1, want 2
+	// example.com/a TestA [    a_test.go:10: got 1, want 2]
+}
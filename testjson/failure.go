@@ -0,0 +1,76 @@
+package testjson
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// ExtractFailTestMessage extracts the error messages of the failed tests from the output lines
+// of 'go test -v'. It returns the "--- FAIL" lines and the messages printed by the failed tests,
+// which are indented by four spaces.
+func ExtractFailTestMessage(testResultMsgs []string) []string {
+	failTestMessages := []string{}
+	beforeRunPos := 0
+	lastFailPos := 0
+	lastRunMsg := ""
+
+	for i, msg := range testResultMsgs {
+		switch {
+		case strings.Contains(msg, "=== RUN"):
+			if lastRunMsg != "" && strings.Contains(msg, fmt.Sprintf("%s/", lastRunMsg)) {
+				continue
+			}
+
+			if beforeRunPos < lastFailPos {
+				for _, v := range testResultMsgs[beforeRunPos:lastFailPos] {
+					if IsRecordableErrorMessage(v) {
+						failTestMessages = append(failTestMessages, "    "+v)
+					}
+				}
+			}
+			lastRunMsg = extractStringBeforeThrash(msg)
+			beforeRunPos = i
+		case strings.Contains(msg, "--- FAIL"):
+			lastFailPos = i
+			failTestMessages = append(failTestMessages, msg)
+		case isFuzzFailureReport(msg) && lastFailPos > beforeRunPos:
+			// The fuzz target prints the error messages and the failing input after the "--- FAIL" lines.
+			lastFailPos = i + 1
+		default:
+		}
+	}
+
+	if beforeRunPos < lastFailPos {
+		for _, v := range testResultMsgs[beforeRunPos:lastFailPos] {
+			if IsRecordableErrorMessage(v) {
+				failTestMessages = append(failTestMessages, "    "+v)
+			}
+		}
+	}
+	return failTestMessages
+}
+
+// extractStringBeforeThrash extracts the string before the slash.
+func extractStringBeforeThrash(s string) string {
+	if index := strings.Index(s, "/"); index != -1 {
+		return s[:index]
+	}
+	return s
+}
+
+// IsRecordableErrorMessage returns true if the string is a recordable error message.
+// The status lines printed by 'go test' ("=== RUN", "--- FAIL", etc.), the progress lines of the fuzzing
+// and blank lines are not recordable.
+func IsRecordableErrorMessage(s string) bool {
+	return !IsFuzzProgress(s) &&
+		!strings.Contains(s, "--- FAIL") &&
+		!strings.Contains(s, "--- PASS") &&
+		!strings.Contains(s, "--- SKIP") &&
+		!strings.Contains(s, "=== RUN") &&
+		!strings.Contains(s, "=== CONT") &&
+		!strings.Contains(s, "=== PAUSE") &&
+		!strings.Contains(s, "=== CONT") &&
+		!strings.Contains(s, "=== NAME") &&
+		strings.TrimRightFunc(s, unicode.IsSpace) != ""
+}
@@ -0,0 +1,159 @@
+package testjson
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+const (
+	// fuzzProgressPrefix is the prefix of the progress lines of the fuzzing,
+	// e.g. "fuzz: elapsed: 3s, execs: 84026 (28007/sec), new interesting: 0 (total: 2)".
+	fuzzProgressPrefix = "fuzz: "
+	// fuzzInputPrefix is the prefix of the line that reports the file of the failing input.
+	fuzzInputPrefix = "Failing input written to "
+	// fuzzRerunPrefix is the prefix of the command that re-runs the failing input.
+	fuzzRerunPrefix = "go test -run="
+)
+
+// fuzzExecsRegexp matches the progress line of the fuzzing that reports the number of executions.
+var fuzzExecsRegexp = regexp.MustCompile(`^fuzz: elapsed: (\S+), execs: (\d+) \((\d+)/sec\), new interesting: (\d+) \(total: (\d+)\)`)
+
+// FuzzProgress is the last progress of a fuzz target.
+type FuzzProgress struct {
+	// Source is the label of the log that the progress is read from.
+	// It is empty unless the results of multiple logs are merged.
+	Source string
+	// Package is the package name of the fuzz target.
+	Package string
+	// Name is the name of the fuzz target.
+	Name string
+	// Elapsed is the elapsed time of the fuzzing that go test prints, e.g. "3s".
+	Elapsed string
+	// Execs is the number of executions of the fuzz function.
+	Execs int64
+	// ExecsPerSec is the number of executions per second in the last interval of the progress.
+	ExecsPerSec int64
+	// NewInteresting is the number of the new inputs that expanded the coverage.
+	// They are added to the corpus in the build cache ($GOCACHE/fuzz).
+	NewInteresting int
+	// TotalInteresting is the number of inputs in the corpus, including the seed corpus.
+	TotalInteresting int
+}
+
+// FuzzFailure is the failing input that the fuzzing found.
+type FuzzFailure struct {
+	// Source is the label of the log that the failure is read from.
+	// It is empty unless the results of multiple logs are merged.
+	Source string
+	// Package is the package name of the fuzz target.
+	Package string
+	// Name is the name of the fuzz target.
+	Name string
+	// InputPath is the file of the minimized failing input relative to the package directory,
+	// e.g. "testdata/fuzz/FuzzX/1de061fa29cfbb3d". The file is a new entry of the seed corpus.
+	InputPath string
+}
+
+// IsFuzzProgress returns true if the line is a progress line of the fuzzing,
+// e.g. "fuzz: elapsed: 0s, gathering baseline coverage: 0/1 completed".
+func IsFuzzProgress(s string) bool {
+	return strings.HasPrefix(strings.TrimSpace(s), fuzzProgressPrefix)
+}
+
+// FuzzInputPath returns the path in the line that reports the file of the failing input,
+// e.g. "Failing input written to testdata/fuzz/FuzzX/1de061fa29cfbb3d".
+func FuzzInputPath(s string) (string, bool) {
+	s = strings.TrimSpace(s)
+	if !strings.HasPrefix(s, fuzzInputPrefix) {
+		return "", false
+	}
+	return strings.TrimPrefix(s, fuzzInputPrefix), true
+}
+
+// isFuzzFailureReport returns true if the line is a part of the report of the failing input
+// that go test prints after the "--- FAIL" line of the fuzz target.
+func isFuzzFailureReport(s string) bool {
+	s = strings.TrimSpace(s)
+	return strings.HasPrefix(s, fuzzInputPrefix) || s == "To re-run:" || strings.HasPrefix(s, fuzzRerunPrefix)
+}
+
+// ParseFuzzProgress parses the progress line that reports the number of executions.
+// It returns false if the line is not such a progress line.
+func ParseFuzzProgress(line string) (*FuzzProgress, bool) {
+	m := fuzzExecsRegexp.FindStringSubmatch(strings.TrimSpace(line))
+	if m == nil {
+		return nil, false
+	}
+	ints := make([]int64, 0, len(m)-2)
+	for _, v := range m[2:] {
+		n, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			return nil, false
+		}
+		ints = append(ints, n)
+	}
+	return &FuzzProgress{
+		Elapsed:          m[1],
+		Execs:            ints[0],
+		ExecsPerSec:      ints[1],
+		NewInteresting:   int(ints[2]),
+		TotalInteresting: int(ints[3]),
+	}, true
+}
+
+// RerunCommand returns the command that re-runs the failing input, e.g.
+// "go test -run=FuzzX/1de061fa29cfbb3d example.com/a".
+func (f *FuzzFailure) RerunCommand() string {
+	return fuzzRerunPrefix + f.Name + "/" + f.InputPath[strings.LastIndex(f.InputPath, "/")+1:] + " " + f.Package
+}
+
+// Label returns the source label of the progress, e.g. "[linux] ".
+// It returns an empty string if the progress does not have a source.
+func (p *FuzzProgress) Label() string {
+	if p.Source == "" {
+		return ""
+	}
+	return "[" + p.Source + "] "
+}
+
+// Label returns the source label of the failure, e.g. "[linux] ".
+// It returns an empty string if the failure does not have a source.
+func (f *FuzzFailure) Label() string {
+	if f.Source == "" {
+		return ""
+	}
+	return "[" + f.Source + "] "
+}
+
+// FuzzProgresses returns the last progress of each fuzz target in the order of appearance.
+// The fuzz targets that are not fuzzed (without -fuzz) are omitted.
+func (r *Results) FuzzProgresses() []*FuzzProgress {
+	progresses := []*FuzzProgress{}
+	for _, t := range r.Tests("") {
+		var last *FuzzProgress
+		for _, v := range t.Output {
+			if p, ok := ParseFuzzProgress(v); ok {
+				last = p
+			}
+		}
+		if last != nil {
+			last.Source, last.Package, last.Name = t.Source, t.Package, t.Name
+			progresses = append(progresses, last)
+		}
+	}
+	return progresses
+}
+
+// FuzzFailures returns the failing inputs that the fuzzing found in the order of appearance.
+func (r *Results) FuzzFailures() []*FuzzFailure {
+	failures := []*FuzzFailure{}
+	for _, t := range r.Tests(ActionFail) {
+		for _, v := range t.Output {
+			if path, ok := FuzzInputPath(v); ok {
+				failures = append(failures, &FuzzFailure{Source: t.Source, Package: t.Package, Name: t.Name, InputPath: path})
+			}
+		}
+	}
+	return failures
+}
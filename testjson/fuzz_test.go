@@ -0,0 +1,62 @@
+package testjson
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestParseFuzzProgress(t *testing.T) {
+	t.Run("Parse the progress line with the number of executions", func(t *testing.T) {
+		got, ok := ParseFuzzProgress("fuzz: elapsed: 3s, execs: 84026 (28007/sec), new interesting: 1 (total: 2)")
+		if !ok {
+			t.Fatal("ParseFuzzProgress() should parse the line")
+		}
+		want := &FuzzProgress{Elapsed: "3s", Execs: 84026, ExecsPerSec: 28007, NewInteresting: 1, TotalInteresting: 2}
+		if diff := cmp.Diff(want, got); diff != "" {
+			t.Errorf("ParseFuzzProgress() mismatch (-want +got):\n%s", diff)
+		}
+	})
+
+	t.Run("The progress of the baseline coverage is not parsed", func(t *testing.T) {
+		if _, ok := ParseFuzzProgress("fuzz: elapsed: 0s, gathering baseline coverage: 0/1 completed"); ok {
+			t.Error("ParseFuzzProgress() should not parse the line")
+		}
+	})
+}
+
+func TestResults_Fuzz(t *testing.T) {
+	agg := aggregateLog(t, "../testdata/fuzz.json")
+
+	t.Run("Find the failing input and the progress of the fuzz target", func(t *testing.T) {
+		failures := agg.Results.FuzzFailures()
+		want := []*FuzzFailure{
+			{Package: "example.com/sample/fuzz", Name: "FuzzReverse", InputPath: "testdata/fuzz/FuzzReverse/1de061fa29cfbb3d"},
+		}
+		if diff := cmp.Diff(want, failures); diff != "" {
+			t.Errorf("FuzzFailures() mismatch (-want +got):\n%s", diff)
+		}
+		if diff := cmp.Diff("go test -run=FuzzReverse/1de061fa29cfbb3d example.com/sample/fuzz", failures[0].RerunCommand()); diff != "" {
+			t.Errorf("RerunCommand() mismatch (-want +got):\n%s", diff)
+		}
+
+		progresses := agg.Results.FuzzProgresses()
+		if len(progresses) != 1 || progresses[0].Execs != 84026 || progresses[0].Name != "FuzzReverse" {
+			t.Errorf("unexpected progresses: %+v", progresses)
+		}
+	})
+
+	t.Run("Extract the error messages and the failing input without the progress lines", func(t *testing.T) {
+		want := []string{
+			"--- FAIL: FuzzReverse (0.03s)",
+			"    --- FAIL: FuzzReverse (0.00s)",
+			"            f_test.go:3: bad input \"x000\"",
+			"        Failing input written to testdata/fuzz/FuzzReverse/1de061fa29cfbb3d",
+			"        To re-run:",
+			"        go test -run=FuzzReverse/1de061fa29cfbb3d",
+		}
+		if diff := cmp.Diff(want, agg.FailMessages()); diff != "" {
+			t.Errorf("FailMessages() mismatch (-want +got):\n%s", diff)
+		}
+	})
+}
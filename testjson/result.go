@@ -0,0 +1,352 @@
+package testjson
+
+import (
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// timeoutPanicPrefix is the prefix of the panic message of the test binary that exceeds -timeout,
+// e.g. "panic: test timed out after 10m0s".
+const timeoutPanicPrefix = "panic: test timed out after "
+
+// TestResult holds the result of a single test.
+type TestResult struct {
+	// Source is the label of the log that the test result is read from.
+	// It is empty unless the results of multiple logs are merged.
+	Source string
+	// Package is the package name of the test.
+	Package string
+	// Name is the test name. Subtests are separated by slashes.
+	Name string
+	// Action is the final action of the test: "pass", "fail" or "skip".
+	// It is empty while the test is running.
+	Action string
+	// Elapsed is the elapsed time of the test in seconds.
+	Elapsed float64
+	// Output is the output of the test. Trailing spaces are removed.
+	Output []string
+}
+
+// PackageResult holds the result of a single package.
+type PackageResult struct {
+	// Source is the label of the log that the package result is read from.
+	// It is empty unless the results of multiple logs are merged.
+	Source string
+	// Name is the package name.
+	Name string
+	// Action is the final action of the package: "pass", "fail" or "skip".
+	Action string
+	// Elapsed is the elapsed time of the package in seconds.
+	Elapsed float64
+	// Stats is the test statistics of the package.
+	Stats TestStats
+	// Tests is the list of tests in the order of appearance.
+	Tests []*TestResult
+	// Output is the output of the package that does not belong to any test.
+	Output []string
+	// Benchmarks is the list of benchmark results in the order of appearance.
+	Benchmarks []*Benchmark
+	// FailedBuild is the ImportPath of the build that failed, e.g. "example.com/a [example.com/a.test]".
+	// It is empty if the package is built or go test does not report it (before Go 1.24).
+	FailedBuild string
+}
+
+// Results holds the per-package and per-test results.
+type Results struct {
+	// Packages is the list of packages in the order of appearance.
+	Packages []*PackageResult
+	// Started is the time of the earliest test event.
+	Started time.Time
+	// Finished is the time of the latest test event.
+	Finished time.Time
+
+	packages map[string]*PackageResult
+	tests    map[string]*TestResult
+	// partialLines is the benchmark output that does not end with a newline yet.
+	partialLines map[string]string
+	// buildOutput is the build output keyed by the source and the ImportPath.
+	buildOutput map[string][]string
+}
+
+// NewResults returns an empty Results.
+func NewResults() *Results {
+	return &Results{
+		Packages:     []*PackageResult{},
+		packages:     map[string]*PackageResult{},
+		tests:        map[string]*TestResult{},
+		partialLines: map[string]string{},
+		buildOutput:  map[string][]string{},
+	}
+}
+
+// Record updates the results with a test output event.
+func (r *Results) Record(o TestOutputJSON) {
+	if o.Action == ActionBuildOutput {
+		r.buildOutput[o.ImportPath] = append(r.buildOutput[o.ImportPath], strings.TrimRightFunc(o.Output, unicode.IsSpace))
+		return
+	}
+	if o.Package == "" {
+		return
+	}
+	if !o.Time.IsZero() {
+		if r.Started.IsZero() || o.Time.Before(r.Started) {
+			r.Started = o.Time
+		}
+		if o.Time.After(r.Finished) {
+			r.Finished = o.Time
+		}
+	}
+	pkg := r.pkg(o.Package)
+
+	if o.Test == "" {
+		switch o.Action {
+		case ActionPass, ActionFail, ActionSkip:
+			pkg.Action = o.Action
+			pkg.Elapsed = o.Elapsed
+			pkg.FailedBuild = o.FailedBuild
+		case ActionOutput:
+			pkg.Output = append(pkg.Output, strings.TrimRightFunc(o.Output, unicode.IsSpace))
+			r.recordBenchmark(pkg, "", o.Output)
+		}
+		return
+	}
+
+	test := r.test(pkg, o.Test)
+	switch o.Action {
+	case ActionPass, ActionFail, ActionSkip:
+		test.Action = o.Action
+		test.Elapsed = o.Elapsed
+		switch o.Action {
+		case ActionPass:
+			pkg.Stats.Pass++
+		case ActionFail:
+			pkg.Stats.Fail++
+		case ActionSkip:
+			pkg.Stats.Skip++
+		}
+		pkg.Stats.Total++
+	case ActionOutput:
+		test.Output = append(test.Output, strings.TrimRightFunc(o.Output, unicode.IsSpace))
+		r.recordBenchmark(pkg, o.Test, o.Output)
+	}
+}
+
+// pkg returns the package result. If it does not exist, it is created.
+func (r *Results) pkg(name string) *PackageResult {
+	if p, ok := r.packages[name]; ok {
+		return p
+	}
+	p := &PackageResult{Name: name, Tests: []*TestResult{}, Output: []string{}, Benchmarks: []*Benchmark{}}
+	r.packages[p.Key()] = p
+	r.Packages = append(r.Packages, p)
+	return p
+}
+
+// test returns the test result. If it does not exist, it is created.
+func (r *Results) test(pkg *PackageResult, name string) *TestResult {
+	key := pkg.Key() + " " + name
+	if t, ok := r.tests[key]; ok {
+		return t
+	}
+	t := &TestResult{Source: pkg.Source, Package: pkg.Name, Name: name, Output: []string{}}
+	r.tests[key] = t
+	pkg.Tests = append(pkg.Tests, t)
+	return t
+}
+
+// Add adds the results read from another log. The results are labeled with the source.
+// The results of other must not be used after Add because they are owned by r.
+func (r *Results) Add(source string, other *Results) {
+	for _, p := range other.Packages {
+		p.Source = source
+		for _, t := range p.Tests {
+			t.Source = source
+			r.tests[p.Key()+" "+t.Name] = t
+		}
+		for _, b := range p.Benchmarks {
+			b.Source = source
+		}
+		r.packages[p.Key()] = p
+		r.Packages = append(r.Packages, p)
+	}
+	for importPath, lines := range other.buildOutput {
+		r.buildOutput[packageKey(source, importPath)] = lines
+	}
+	if !other.Started.IsZero() && (r.Started.IsZero() || other.Started.Before(r.Started)) {
+		r.Started = other.Started
+	}
+	if other.Finished.After(r.Finished) {
+		r.Finished = other.Finished
+	}
+}
+
+// HasSources returns true if the results are merged from multiple labeled logs.
+func (r *Results) HasSources() bool {
+	for _, p := range r.Packages {
+		if p.Source != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// Key returns the key that identifies the package in the results, e.g. "[linux] example.com/a".
+func (p *PackageResult) Key() string {
+	return packageKey(p.Source, p.Name)
+}
+
+// packageKey returns the key that identifies the package of the source in the results.
+func packageKey(source, name string) string {
+	if source == "" {
+		return name
+	}
+	return "[" + source + "] " + name
+}
+
+// BuildFailed returns true if the package failed to build or to set up, e.g. a compile error or an import cycle.
+func (p *PackageResult) BuildFailed() bool {
+	if p.Action != ActionFail {
+		return false
+	}
+	if p.FailedBuild != "" {
+		return true
+	}
+	for _, v := range p.Output {
+		if strings.HasSuffix(v, "[build failed]") || strings.HasSuffix(v, "[setup failed]") {
+			return true
+		}
+	}
+	return false
+}
+
+// TimedOut returns true if the test binary of the package panicked because the tests exceeded -timeout.
+func (p *PackageResult) TimedOut() bool {
+	if p.Action != ActionFail {
+		return false
+	}
+	for _, t := range p.Tests {
+		for _, v := range t.Output {
+			if strings.HasPrefix(v, timeoutPanicPrefix) {
+				return true
+			}
+		}
+	}
+	for _, v := range p.Output {
+		if strings.HasPrefix(v, timeoutPanicPrefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// Unfinished returns the tests of the package that did not finish,
+// e.g. the tests that were running when the test binary timed out.
+func (p *PackageResult) Unfinished() []*TestResult {
+	tests := []*TestResult{}
+	for _, t := range p.Tests {
+		if t.Action == "" {
+			tests = append(tests, t)
+		}
+	}
+	return tests
+}
+
+// BuildOutput returns the build output of the package that failed to build, e.g. the compile errors.
+// It returns nil if go test does not report the build output as events (before Go 1.24).
+func (r *Results) BuildOutput(p *PackageResult) []string {
+	return r.buildOutput[packageKey(p.Source, p.FailedBuild)]
+}
+
+// Find returns the result of the test in the package that is not labeled with a source.
+// It returns nil if the test has not been recorded.
+func (r *Results) Find(pkg, name string) *TestResult {
+	return r.tests[pkg+" "+name]
+}
+
+// Tests returns all tests with the specified action in the order of appearance.
+// If action is empty, all tests are returned.
+func (r *Results) Tests(action string) []*TestResult {
+	tests := []*TestResult{}
+	for _, p := range r.Packages {
+		for _, t := range p.Tests {
+			if action == "" || t.Action == action {
+				tests = append(tests, t)
+			}
+		}
+	}
+	return tests
+}
+
+// Slowest returns the n slowest passed or failed tests in descending order of elapsed time.
+func (r *Results) Slowest(n int) []*TestResult {
+	tests := []*TestResult{}
+	for _, t := range r.Tests("") {
+		if t.Action == ActionPass || t.Action == ActionFail {
+			tests = append(tests, t)
+		}
+	}
+	sort.SliceStable(tests, func(i, j int) bool {
+		return tests[i].Elapsed > tests[j].Elapsed
+	})
+	if len(tests) > n {
+		tests = tests[:n]
+	}
+	return tests
+}
+
+// Label returns the source label of the test, e.g. "[linux] ".
+// It returns an empty string if the test does not have a source.
+func (t *TestResult) Label() string {
+	if t.Source == "" {
+		return ""
+	}
+	return "[" + t.Source + "] "
+}
+
+// Messages returns the test output without the status lines that go test prints
+// ("=== RUN", "--- FAIL", etc.) and blank lines.
+func (t *TestResult) Messages() []string {
+	msgs := []string{}
+	for _, v := range t.Output {
+		if IsRecordableErrorMessage(v) {
+			msgs = append(msgs, v)
+		}
+	}
+	return msgs
+}
+
+// SkipReason returns the reason the test was skipped.
+// The reason is the message passed to t.Skip(), such as "main_test.go:10: not supported".
+func (t *TestResult) SkipReason() string {
+	msgs := t.Messages()
+	for i, v := range msgs {
+		msgs[i] = strings.TrimSpace(v)
+	}
+	return strings.Join(msgs, " ")
+}
+
+// Failures returns the failed tests that have their own error messages in the order of appearance.
+// A parent test that fails only because its subtests fail is omitted.
+func (r *Results) Failures() []*TestResult {
+	failures := []*TestResult{}
+	for _, t := range r.Tests(ActionFail) {
+		if len(t.Messages()) == 0 && r.hasFailedSubtest(t) {
+			continue
+		}
+		failures = append(failures, t)
+	}
+	return failures
+}
+
+// hasFailedSubtest returns true if one of the subtests of t failed.
+func (r *Results) hasFailedSubtest(t *TestResult) bool {
+	p := r.packages[packageKey(t.Source, t.Package)]
+	for _, v := range p.Tests {
+		if v.Action == ActionFail && strings.HasPrefix(v.Name, t.Name+"/") {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,144 @@
+package testjson
+
+import (
+	"os"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+// aggregateLog returns an Aggregator that aggregated the go test -json log in testdata.
+func aggregateLog(t *testing.T, path string) *Aggregator {
+	t.Helper()
+
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close() //nolint
+
+	agg := NewAggregator()
+	for e := range Decode(f) {
+		if e.Err != nil {
+			t.Fatal(e.Err)
+		}
+		agg.Add(e.Output)
+	}
+	return agg
+}
+
+func TestResults(t *testing.T) {
+	r := aggregateLog(t, "../testdata/sample.json").Results
+
+	t.Run("Record per-package results in the order of appearance", func(t *testing.T) {
+		got := [][]interface{}{}
+		for _, p := range r.Packages {
+			got = append(got, []interface{}{p.Name, p.Action, p.Stats})
+		}
+		want := [][]interface{}{
+			{"example.com/sample/a", ActionFail, TestStats{Pass: 2, Fail: 2, Skip: 1, Total: 5}},
+			{"example.com/sample/b", ActionPass, TestStats{Pass: 1, Total: 1}},
+		}
+		if diff := cmp.Diff(want, got); diff != "" {
+			t.Errorf("Packages mismatch (-want +got):\n%s", diff)
+		}
+	})
+
+	t.Run("Failures omit a parent test that fails only because of its subtests", func(t *testing.T) {
+		failures := r.Failures()
+		if len(failures) != 1 {
+			t.Fatalf("Failures() returns %d tests, want 1", len(failures))
+		}
+		if diff := cmp.Diff("TestFail/sub_ng", failures[0].Name); diff != "" {
+			t.Errorf("Failures() mismatch (-want +got):\n%s", diff)
+		}
+		if diff := cmp.Diff([]string{"    a_test.go:10: got 1, want 2"}, failures[0].Messages()); diff != "" {
+			t.Errorf("Messages() mismatch (-want +got):\n%s", diff)
+		}
+	})
+
+	t.Run("Get skip reason", func(t *testing.T) {
+		skipped := r.Tests(ActionSkip)
+		if len(skipped) != 1 {
+			t.Fatalf("Tests(skip) returns %d tests, want 1", len(skipped))
+		}
+		if diff := cmp.Diff("a_test.go:15: not supported on this platform", skipped[0].SkipReason()); diff != "" {
+			t.Errorf("SkipReason() mismatch (-want +got):\n%s", diff)
+		}
+	})
+
+	t.Run("Find a test", func(t *testing.T) {
+		if got := r.Find("example.com/sample/b", "TestB"); got == nil || got.Action != ActionPass {
+			t.Errorf("Find() = %v, want passed TestB", got)
+		}
+		if got := r.Find("example.com/sample/b", "TestNotExist"); got != nil {
+			t.Errorf("Find() = %v, want nil", got)
+		}
+	})
+
+	t.Run("Get the slowest tests", func(t *testing.T) {
+		r := NewResults()
+		r.Record(TestOutputJSON{Action: ActionPass, Package: "p", Test: "TestA", Elapsed: 0.1})
+		r.Record(TestOutputJSON{Action: ActionPass, Package: "p", Test: "TestB", Elapsed: 2})
+		r.Record(TestOutputJSON{Action: ActionRun, Package: "p", Test: "TestRunning"})
+		r.Record(TestOutputJSON{Action: ActionFail, Package: "p", Test: "TestC", Elapsed: 1})
+
+		got := []string{}
+		for _, v := range r.Slowest(2) {
+			got = append(got, v.Name)
+		}
+		if diff := cmp.Diff([]string{"TestB", "TestC"}, got); diff != "" {
+			t.Errorf("Slowest() mismatch (-want +got):\n%s", diff)
+		}
+	})
+}
+
+func TestPackageResult_BuildFailed(t *testing.T) {
+	t.Run("Detect the build failure and record the build output", func(t *testing.T) {
+		r := aggregateLog(t, "../testdata/build.json").Results
+		p := r.Packages[0]
+		if !p.BuildFailed() {
+			t.Errorf("BuildFailed() = false, want true")
+		}
+		want := []string{"# example.com/x/bad [example.com/x/bad.test]", "bad/bad.go:3:12: undefined: undefined"}
+		if diff := cmp.Diff(want, r.BuildOutput(p)); diff != "" {
+			t.Errorf("BuildOutput() mismatch (-want +got):\n%s", diff)
+		}
+	})
+
+	t.Run("Detect the build failure from the output of go test before Go 1.24", func(t *testing.T) {
+		r := NewResults()
+		r.Record(TestOutputJSON{Action: ActionOutput, Package: "example.com/a", Output: "FAIL\texample.com/a [build failed]\n"})
+		r.Record(TestOutputJSON{Action: ActionFail, Package: "example.com/a"})
+		if !r.Packages[0].BuildFailed() {
+			t.Errorf("BuildFailed() = false, want true")
+		}
+	})
+
+	t.Run("A failed test is not a build failure", func(t *testing.T) {
+		r := aggregateLog(t, "../testdata/sample.json").Results
+		if r.Packages[0].BuildFailed() {
+			t.Errorf("BuildFailed() = true, want false")
+		}
+	})
+}
+
+func TestPackageResult_TimedOut(t *testing.T) {
+	t.Run("Detect the timeout and the test that was running", func(t *testing.T) {
+		p := aggregateLog(t, "../testdata/timeout.json").Results.Packages[0]
+		if !p.TimedOut() {
+			t.Errorf("TimedOut() = false, want true")
+		}
+		unfinished := p.Unfinished()
+		if len(unfinished) != 1 || unfinished[0].Name != "TestSlow" {
+			t.Errorf("Unfinished() = %v, want [TestSlow]", unfinished)
+		}
+	})
+
+	t.Run("A failed test is not a timeout", func(t *testing.T) {
+		p := aggregateLog(t, "../testdata/sample.json").Results.Packages[0]
+		if p.TimedOut() {
+			t.Errorf("TimedOut() = true, want false")
+		}
+	})
+}